@@ -2,12 +2,29 @@ package form3
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 )
 
 // Defaults
-const defaultAccountsPath = "organisation/accounts"
+const (
+	defaultAccountsPath     = "organisation/accounts"
+	defaultAccountsTemplate = defaultAccountsPath + "/{id}"
+)
+
+// Account operation names, as seen by middlewares.
+const (
+	OperationCreateAccount = "accounts.create"
+	OperationDeleteAccount = "accounts.delete"
+	OperationFetchAccount  = "accounts.fetch"
+	OperationListAccounts  = "accounts.list"
+	OperationUpdateAccount = "accounts.update"
+)
 
 // HTTP entities
 // Ref: https://www.api-docs.form3.tech/api/schemes/fps-direct/accounts/accounts/create-an-account
@@ -19,60 +36,226 @@ type CreateAccountData struct {
 	Attributes     *CreateAccountAttributes `json:"attributes,omitempty"`
 }
 type CreateAccountAttributes struct {
-	BankID                  string    `json:"bank_id"`
-	BankIDCode              string    `json:"bank_id_code"`
-	Bic                     string    `json:"bic"`
-	Country                 string    `json:"country"`
-	Name                    []string  `json:"name,omitempty"`
-	AccountClassification   *string   `json:"account_classification,omitempty"`
-	AccountNumber           *string   `json:"account_number,omitempty"`
-	AlternativeNames        *[]string `json:"alternative_names,omitempty"`
-	BaseCurrency            *string   `json:"base_currency,omitempty"`
-	Iban                    *string   `json:"iban,omitempty"`
-	JointAccount            *bool     `json:"joint_account,omitempty"`
-	SecondaryIdentification *string   `json:"secondary_identification,omitempty"`
+	BankID                     string                             `json:"bank_id"`
+	BankIDCode                 string                             `json:"bank_id_code"`
+	Bic                        string                             `json:"bic"`
+	Country                    string                             `json:"country"`
+	Name                       []string                           `json:"name,omitempty"`
+	AcceptanceQualifier        *string                            `json:"acceptance_qualifier,omitempty"`
+	AccountClassification      *string                            `json:"account_classification,omitempty"`
+	AccountMatchingOptOut      *bool                              `json:"account_matching_opt_out,omitempty"`
+	AccountNumber              *string                            `json:"account_number,omitempty"`
+	AlternativeNames           *[]string                          `json:"alternative_names,omitempty"`
+	BaseCurrency               *string                            `json:"base_currency,omitempty"`
+	Iban                       *string                            `json:"iban,omitempty"`
+	JointAccount               *bool                              `json:"joint_account,omitempty"`
+	NameMatchingStatus         *string                            `json:"name_matching_status,omitempty"`
+	OrganisationIdentification *AccountOrganisationIdentification `json:"organisation_identification,omitempty"`
+	PrivateIdentification      *AccountPrivateIdentification      `json:"private_identification,omitempty"`
+	ProcessingService          *string                            `json:"processing_service,omitempty"`
+	ReferenceMask              *string                            `json:"reference_mask,omitempty"`
+	SecondaryIdentification    *string                            `json:"secondary_identification,omitempty"`
+	Status                     *string                            `json:"status,omitempty"`
+	Switched                   *bool                              `json:"switched,omitempty"`
+	UserDefinedData            []AccountUserDefinedData           `json:"user_defined_data,omitempty"`
+	UserDefinedInformation     *string                            `json:"user_defined_information,omitempty"`
+	ValidationType             *string                            `json:"validation_type,omitempty"`
 }
 type CreateAccountResponse = Form3BodyResponse[Account]
 
 // Ref: https://www.api-docs.form3.tech/api/schemes/fps-direct/accounts/accounts/fetch-an-account
 type FetchAccountResponse = Form3BodyResponse[Account]
 
+// Ref: https://www.api-docs.form3.tech/api/schemes/fps-direct/accounts/accounts/list-accounts
+type ListAccountsResponse = Form3BodyResponse[[]Account]
+
+// ListAccountsOptions are the pagination and filter options of AccountService.List. Empty fields are not sent.
+type ListAccountsOptions struct {
+	// PageNumber is the number of the page, starting from 0.
+	PageNumber int
+
+	// PageSize is the number of accounts per page. The Form3 API default is used if zero.
+	PageSize int
+
+	BankID        string
+	BankIDCode    string
+	AccountNumber string
+	Iban          string
+	Country       string
+	CustomerID    string
+}
+
+// query returns the query string of the options.
+func (o *ListAccountsOptions) query() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+
+	if o.PageNumber > 0 {
+		query.Set("page[number]", strconv.Itoa(o.PageNumber))
+	}
+	if o.PageSize > 0 {
+		query.Set("page[size]", strconv.Itoa(o.PageSize))
+	}
+
+	filters := map[string]string{
+		"filter[bank_id]":        o.BankID,
+		"filter[bank_id_code]":   o.BankIDCode,
+		"filter[account_number]": o.AccountNumber,
+		"filter[iban]":           o.Iban,
+		"filter[country]":        o.Country,
+		"filter[customer_id]":    o.CustomerID,
+	}
+	for key, value := range filters {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+
+	return query
+}
+
+// listAccountsURL returns the URL listing the accounts matching the given options.
+func listAccountsURL(opts *ListAccountsOptions) string {
+	if query := opts.query().Encode(); query != "" {
+		return defaultAccountsPath + "?" + query
+	}
+	return defaultAccountsPath
+}
+
+// Ref: https://www.api-docs.form3.tech/api/schemes/fps-direct/accounts/accounts/patch-an-account
+type UpdateAccountRequest = Form3BodyRequest[UpdateAccountData]
+type UpdateAccountData struct {
+	ID         string                   `json:"id,omitempty"`
+	Type       string                   `json:"type,omitempty"`
+	Version    int64                    `json:"version"`
+	Attributes *UpdateAccountAttributes `json:"attributes,omitempty"`
+}
+
+// UpdateAccountAttributes are the account attributes changed by AccountService.Update.
+// Nil fields are left unchanged, while pointers to empty values clear the attribute.
+type UpdateAccountAttributes struct {
+	AccountClassification   *string   `json:"account_classification,omitempty"`
+	AlternativeNames        *[]string `json:"alternative_names,omitempty"`
+	Name                    *[]string `json:"name,omitempty"`
+	SecondaryIdentification *string   `json:"secondary_identification,omitempty"`
+}
+type UpdateAccountResponse = Form3BodyResponse[Account]
+
 // Business models
 type Account struct {
-	Attributes     *AccountAttributes `json:"attributes,omitempty"`
-	ID             string             `json:"id,omitempty"`
-	OrganisationID string             `json:"organisation_id,omitempty"`
-	Type           string             `json:"type,omitempty"`
-	CreatedOn      string             `json:"created_on,omitempty"`
-	ModifiedOn     string             `json:"modified_on,omitempty"`
-	Version        *int64             `json:"version,omitempty"`
+	Attributes     *AccountAttributes    `json:"attributes,omitempty"`
+	ID             string                `json:"id,omitempty"`
+	OrganisationID string                `json:"organisation_id,omitempty"`
+	Type           string                `json:"type,omitempty"`
+	CreatedOn      string                `json:"created_on,omitempty"`
+	ModifiedOn     string                `json:"modified_on,omitempty"`
+	Version        *int64                `json:"version,omitempty"`
+	Relationships  *AccountRelationships `json:"relationships,omitempty"`
 }
 type AccountAttributes struct {
-	AccountClassification   *string  `json:"account_classification,omitempty"`
-	AccountMatchingOptOut   *bool    `json:"account_matching_opt_out,omitempty"`
-	AccountNumber           string   `json:"account_number,omitempty"`
-	AlternativeNames        []string `json:"alternative_names,omitempty"`
-	BankID                  string   `json:"bank_id,omitempty"`
-	BankIDCode              string   `json:"bank_id_code,omitempty"`
-	BaseCurrency            string   `json:"base_currency,omitempty"`
-	Bic                     string   `json:"bic,omitempty"`
-	Country                 *string  `json:"country,omitempty"`
-	Iban                    string   `json:"iban,omitempty"`
-	JointAccount            *bool    `json:"joint_account,omitempty"`
-	Name                    []string `json:"name,omitempty"`
-	SecondaryIdentification string   `json:"secondary_identification,omitempty"`
-	Status                  *string  `json:"status,omitempty"`
-	Switched                *bool    `json:"switched,omitempty"`
+	AcceptanceQualifier        *string                            `json:"acceptance_qualifier,omitempty"`
+	AccountClassification      *string                            `json:"account_classification,omitempty"`
+	AccountMatchingOptOut      *bool                              `json:"account_matching_opt_out,omitempty"`
+	AccountNumber              string                             `json:"account_number,omitempty"`
+	AlternativeNames           []string                           `json:"alternative_names,omitempty"`
+	BankID                     string                             `json:"bank_id,omitempty"`
+	BankIDCode                 string                             `json:"bank_id_code,omitempty"`
+	BaseCurrency               string                             `json:"base_currency,omitempty"`
+	Bic                        string                             `json:"bic,omitempty"`
+	Country                    *string                            `json:"country,omitempty"`
+	Iban                       string                             `json:"iban,omitempty"`
+	JointAccount               *bool                              `json:"joint_account,omitempty"`
+	Name                       []string                           `json:"name,omitempty"`
+	NameMatchingStatus         *string                            `json:"name_matching_status,omitempty"`
+	OrganisationIdentification *AccountOrganisationIdentification `json:"organisation_identification,omitempty"`
+	PrivateIdentification      *AccountPrivateIdentification      `json:"private_identification,omitempty"`
+	ProcessingService          *string                            `json:"processing_service,omitempty"`
+	ReferenceMask              *string                            `json:"reference_mask,omitempty"`
+	SecondaryIdentification    string                             `json:"secondary_identification,omitempty"`
+	Status                     *string                            `json:"status,omitempty"`
+	StatusReason               *string                            `json:"status_reason,omitempty"`
+	Switched                   *bool                              `json:"switched,omitempty"`
+	UserDefinedData            []AccountUserDefinedData           `json:"user_defined_data,omitempty"`
+	UserDefinedInformation     *string                            `json:"user_defined_information,omitempty"`
+	ValidationType             *string                            `json:"validation_type,omitempty"`
+}
+
+// AccountPrivateIdentification identifies the person holding a personal account.
+type AccountPrivateIdentification struct {
+	BirthDate      string   `json:"birth_date,omitempty"`
+	BirthCountry   string   `json:"birth_country,omitempty"`
+	Identification string   `json:"identification,omitempty"`
+	Address        []string `json:"address,omitempty"`
+	City           string   `json:"city,omitempty"`
+	Country        string   `json:"country,omitempty"`
+}
+
+// AccountOrganisationIdentification identifies the organisation holding a business account.
+type AccountOrganisationIdentification struct {
+	Identification string                     `json:"identification,omitempty"`
+	Actors         []AccountOrganisationActor `json:"actors,omitempty"`
+	Address        []string                   `json:"address,omitempty"`
+	City           string                     `json:"city,omitempty"`
+	Country        string                     `json:"country,omitempty"`
+}
+
+// AccountOrganisationActor is a person acting on behalf of the organisation holding an account.
+type AccountOrganisationActor struct {
+	Name      []string `json:"name,omitempty"`
+	BirthDate string   `json:"birth_date,omitempty"`
+	Residency string   `json:"residency,omitempty"`
+}
+
+// AccountUserDefinedData is a key value pair stored with an account.
+type AccountUserDefinedData struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// AccountRelationships are the resources related to an account.
+type AccountRelationships struct {
+	// MasterAccount is the account this account belongs to, if any.
+	MasterAccount *Relationship `json:"master_account,omitempty"`
+
+	// AccountEvents are the events of the account, such as its confirmation.
+	AccountEvents *Relationship `json:"account_events,omitempty"`
+}
+
+// Relationship lists the resources related to another one.
+type Relationship struct {
+	Data []RelationshipData `json:"data"`
+}
+
+// RelationshipData identifies a related resource.
+type RelationshipData struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
 }
 
 // AccountService has methods to communicate with the account related methods of the Form3 API.
 type AccountService struct {
 	// client is the client used to communicate with the Form3 API.
 	client *Client
+
+	// fetches coalesces concurrent fetches of the same account.
+	fetches fetchGroup
 }
 
 // Create creates a new account against the Form3 API.
-func (as *AccountService) Create(ctx context.Context, ID string, organisationID string, attributes *CreateAccountAttributes) (*Account, *Form3BodyResponseLinks, error) {
+// If organisationID is empty, the client default organisation ID is used.
+// The attributes are validated first, and ValidationErrors are returned without sending anything if they are invalid.
+// The response metadata, including the links of the body, is returned whenever the API answered.
+func (as *AccountService) Create(ctx context.Context, ID string, organisationID string, attributes *CreateAccountAttributes) (*Account, *Response, error) {
+	if err := attributes.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("error creating account: %w", err)
+	}
+
+	if organisationID == "" {
+		organisationID = as.client.OrganisationID
+	}
+
 	formData := CreateAccountRequest{
 		Data: CreateAccountData{
 			ID:             ID,
@@ -83,35 +266,203 @@ func (as *AccountService) Create(ctx context.Context, ID string, organisationID
 	}
 
 	accountResponse := CreateAccountResponse{}
-	err := as.client.Do(ctx, http.MethodPost, defaultAccountsPath, formData, &accountResponse)
+	res, err := as.client.do(ctx, &Request{
+		Operation: OperationCreateAccount,
+		Method:    http.MethodPost,
+		URL:       defaultAccountsPath,
+		Template:  defaultAccountsPath,
+		Body:      formData,
+		Result:    &accountResponse,
+	})
 	if err != nil {
-		return nil, nil, fmt.Errorf("error creating account: %w", err)
+		return nil, res, fmt.Errorf("error creating account: %w", err)
 	}
 
-	return &accountResponse.Data, &accountResponse.Links, nil
+	return &accountResponse.Data, res, nil
 }
 
 // Delete deletes an account against the Form3 API.
-func (as *AccountService) Delete(ctx context.Context, ID string, version int64) error {
+func (as *AccountService) Delete(ctx context.Context, ID string, version int64) (*Response, error) {
 	uri := fmt.Sprintf("%s/%s?version=%d", defaultAccountsPath, ID, version)
 
-	err := as.client.Do(ctx, http.MethodDelete, uri, nil, nil)
+	res, err := as.client.do(ctx, &Request{
+		Operation: OperationDeleteAccount,
+		Method:    http.MethodDelete,
+		URL:       uri,
+		Template:  defaultAccountsTemplate,
+	})
+
+	// The account might have been deleted even if the request failed.
+	as.client.invalidate(accountCacheKey(ID))
 	if err != nil {
-		return fmt.Errorf("error deleting account: %w", err)
+		return res, fmt.Errorf("error deleting account: %w", versionConflict(ID, version, err))
 	}
 
-	return nil
+	return res, nil
+}
+
+// Update changes the given attributes of an account against the Form3 API. The version must be the current version
+// of the account, otherwise a *VersionConflictError is returned. The updated account carries its new version.
+func (as *AccountService) Update(ctx context.Context, ID string, version int64, attributes *UpdateAccountAttributes) (*Account, *Response, error) {
+	formData := UpdateAccountRequest{
+		Data: UpdateAccountData{
+			ID:         ID,
+			Type:       "accounts",
+			Version:    version,
+			Attributes: attributes,
+		},
+	}
+
+	accountResponse := UpdateAccountResponse{}
+	res, err := as.client.do(ctx, &Request{
+		Operation: OperationUpdateAccount,
+		Method:    http.MethodPatch,
+		URL:       fmt.Sprintf("%s/%s", defaultAccountsPath, ID),
+		Template:  defaultAccountsTemplate,
+		Body:      formData,
+		Result:    &accountResponse,
+	})
+
+	// The account might have been updated even if the request failed.
+	as.client.invalidate(accountCacheKey(ID))
+	if err != nil {
+		return nil, res, fmt.Errorf("error updating account: %w", versionConflict(ID, version, err))
+	}
+
+	return &accountResponse.Data, res, nil
+}
+
+// List lists a page of the accounts matching the given options, which can be nil, against the Form3 API.
+// The pagination links of the page are returned in the response Links.
+func (as *AccountService) List(ctx context.Context, opts *ListAccountsOptions) ([]Account, *Response, error) {
+	uri := listAccountsURL(opts)
+
+	accountsResponse := ListAccountsResponse{}
+	res, err := as.client.do(ctx, &Request{
+		Operation: OperationListAccounts,
+		Method:    http.MethodGet,
+		URL:       uri,
+		Template:  defaultAccountsPath,
+		Result:    &accountsResponse,
+	})
+	if err != nil {
+		return nil, res, fmt.Errorf("error listing accounts: %w", err)
+	}
+
+	return accountsResponse.Data, res, nil
+}
+
+// ListPager returns a pager over the pages of the accounts matching the given options, which can be nil,
+// starting at the page of the options.
+func (as *AccountService) ListPager(opts *ListAccountsOptions) *Pager[Account] {
+	return NewPager[Account](as.client, OperationListAccounts, listAccountsURL(opts), defaultAccountsPath)
+}
+
+// All returns an iterator over every account matching the given options, which can be nil, following the pages
+// returned by the Form3 API.
+func (as *AccountService) All(ctx context.Context, opts *ListAccountsOptions) iter.Seq2[Account, error] {
+	return as.ListPager(opts).All(ctx)
 }
 
 // Fetch fetches an account against the Form3 API.
-func (as *AccountService) Fetch(ctx context.Context, ID string) (*Account, *Form3BodyResponseLinks, error) {
-	uri := fmt.Sprintf("%s/%s", defaultAccountsPath, ID)
+// Concurrent calls for the same ID share a single request. If the client has a Cache, fresh accounts are returned
+// from it, and expired ones are revalidated with their ETag when the Form3 API sent one.
+func (as *AccountService) Fetch(ctx context.Context, ID string) (*Account, *Response, error) {
+	return as.fetchAccount(ctx, ID, false)
+}
+
+// fetchAccount fetches an account like Fetch does. If bypassCache is set, the account is always fetched from the
+// Form3 API on its own request, for the operations needing its current version.
+func (as *AccountService) fetchAccount(ctx context.Context, ID string, bypassCache bool) (*Account, *Response, error) {
+	fetch := func(ctx context.Context) ([]byte, *Response, error) {
+		return as.fetch(ctx, ID, bypassCache)
+	}
 
-	accountResponse := FetchAccountResponse{}
-	err := as.client.Do(ctx, http.MethodGet, uri, nil, &accountResponse)
+	var (
+		value []byte
+		res   *Response
+		err   error
+	)
+	if bypassCache {
+		value, res, err = fetch(ctx)
+	} else {
+		value, res, err = as.fetches.do(ctx, ID, fetch)
+	}
 	if err != nil {
-		return nil, nil, fmt.Errorf("error fetching account: %w", err)
+		return nil, res, fmt.Errorf("error fetching account: %w", err)
+	}
+
+	// Every caller gets its own copy of the account.
+	account := &Account{}
+	if err := json.Unmarshal(value, account); err != nil {
+		return nil, res, fmt.Errorf("error fetching account: %w", err)
+	}
+
+	return account, res, nil
+}
+
+// fetch fetches an account from the client cache, unless bypassCache is set, or from the Form3 API, and returns it
+// JSON encoded. Accounts fetched from the Form3 API are stored in the cache either way.
+func (as *AccountService) fetch(ctx context.Context, ID string, bypassCache bool) ([]byte, *Response, error) {
+	key := accountCacheKey(ID)
+	cache := as.client.Cache
+
+	var (
+		entry  CacheEntry
+		cached bool
+		set    func(entry CacheEntry)
+	)
+	if cache != nil && !bypassCache {
+		entry, cached = cache.Get(key)
+		if cached && time.Now().Before(entry.Expires) {
+			return entry.Value, &Response{StatusCode: http.StatusOK, ETag: entry.ETag, FromCache: true}, nil
+		}
+	}
+
+	// The account is only stored if it is not invalidated in the meantime, by a deletion for example.
+	if cache != nil {
+		generation := as.client.cacheGenerations.begin(key)
+		defer as.client.cacheGenerations.end(key)
+		set = func(entry CacheEntry) { as.client.setCache(key, generation, entry) }
+	}
+
+	req := &Request{
+		Operation: OperationFetchAccount,
+		Method:    http.MethodGet,
+		URL:       fmt.Sprintf("%s/%s", defaultAccountsPath, ID),
+		Template:  defaultAccountsTemplate,
+		Result:    &FetchAccountResponse{},
+	}
+	if cached && entry.ETag != "" {
+		req.Header = http.Header{"If-None-Match": []string{entry.ETag}}
 	}
 
-	return &accountResponse.Data, &accountResponse.Links, nil
+	res, err := as.client.do(ctx, req)
+	if err != nil {
+		return nil, res, err
+	}
+
+	// The cached account did not change, so it is fresh again.
+	if res.StatusCode == http.StatusNotModified {
+		entry.Expires = time.Now().Add(as.client.CacheTTL)
+		set(entry)
+		res.FromCache = true
+		return entry.Value, res, nil
+	}
+
+	value, err := json.Marshal(req.Result.(*FetchAccountResponse).Data)
+	if err != nil {
+		return nil, res, err
+	}
+
+	if cache != nil {
+		set(CacheEntry{Value: value, ETag: res.ETag, Expires: time.Now().Add(as.client.CacheTTL)})
+	}
+
+	return value, res, nil
+}
+
+// accountCacheKey returns the key of the account in the client cache.
+func accountCacheKey(ID string) string {
+	return defaultAccountsPath + "/" + ID
 }
@@ -0,0 +1,228 @@
+package form3
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAccountService_List(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{
+			"data": [{"id": "10", "version": 0}, {"id": "11", "version": 2}],
+			"links": {"self": "/v1/organisation/accounts?page[number]=1", "first": "/v1/organisation/accounts?page[number]=first", "next": "/v1/organisation/accounts?page[number]=2"}
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		opts      *ListAccountsOptions
+		wantQuery url.Values
+	}{
+		{
+			name:      "Test list without options",
+			opts:      nil,
+			wantQuery: url.Values{},
+		},
+		{
+			name: "Test list with pagination and filters",
+			opts: &ListAccountsOptions{
+				PageNumber:    1,
+				PageSize:      2,
+				BankID:        "400300",
+				BankIDCode:    "GBDSC",
+				AccountNumber: "41426819",
+				Iban:          "GB11NWBK40030041426819",
+				Country:       "GB",
+				CustomerID:    "c1",
+			},
+			wantQuery: url.Values{
+				"page[number]":           {"1"},
+				"page[size]":             {"2"},
+				"filter[bank_id]":        {"400300"},
+				"filter[bank_id_code]":   {"GBDSC"},
+				"filter[account_number]": {"41426819"},
+				"filter[iban]":           {"GB11NWBK40030041426819"},
+				"filter[country]":        {"GB"},
+				"filter[customer_id]":    {"c1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accounts, res, err := client.Account.List(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("AccountService.List() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(query, tt.wantQuery) {
+				t.Fatalf("AccountService.List() - query - got = %v, want %v", query, tt.wantQuery)
+			}
+			if len(accounts) != 2 || accounts[0].ID != "10" || accounts[1].ID != "11" {
+				t.Fatalf("AccountService.List() - accounts - got = %+v, want IDs 10 and 11", accounts)
+			}
+			if res.Links == nil || res.Links.Next != "/v1/organisation/accounts?page[number]=2" {
+				t.Fatalf("AccountService.List() - Links - got = %+v, want a next link", res.Links)
+			}
+		})
+	}
+}
+
+func TestAccountService_Update(t *testing.T) {
+	empty, classification := "", "Business"
+
+	tests := []struct {
+		name        string
+		attributes  *UpdateAccountAttributes
+		conflict    bool
+		wantBody    string
+		wantVersion int64
+		wantErr     error
+	}{
+		{
+			name:        "Test unset attributes are not sent",
+			attributes:  &UpdateAccountAttributes{AccountClassification: &classification},
+			wantBody:    `{"data":{"id":"10","type":"accounts","version":1,"attributes":{"account_classification":"Business"}}}`,
+			wantVersion: 2,
+		},
+		{
+			name:        "Test empty attributes are sent",
+			attributes:  &UpdateAccountAttributes{AlternativeNames: &[]string{}, SecondaryIdentification: &empty},
+			wantBody:    `{"data":{"id":"10","type":"accounts","version":1,"attributes":{"alternative_names":[],"secondary_identification":""}}}`,
+			wantVersion: 2,
+		},
+		{
+			name:       "Test version conflict",
+			attributes: &UpdateAccountAttributes{Name: &[]string{"Jane Doe"}},
+			conflict:   true,
+			wantBody:   `{"data":{"id":"10","type":"accounts","version":1,"attributes":{"name":["Jane Doe"]}}}`,
+			wantErr:    ErrVersionConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var method, body string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				method = r.Method
+				b, _ := io.ReadAll(r.Body)
+				body = string(b)
+
+				if tt.conflict {
+					w.WriteHeader(http.StatusConflict)
+					w.Write([]byte(`{"error_message": "invalid version"}`))
+					return
+				}
+				w.Write([]byte(`{"data": {"id": "10", "version": 2}}`))
+			}))
+			defer server.Close()
+
+			cache := NewLRUCache(10)
+			cache.Set(accountCacheKey("10"), CacheEntry{Value: []byte(`{"id": "10", "version": 1}`), Expires: time.Now().Add(time.Hour)})
+
+			client, err := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithCache(cache, time.Hour))
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			account, _, err := client.Account.Update(context.Background(), "10", 1, tt.attributes)
+			if method != http.MethodPatch {
+				t.Fatalf("AccountService.Update() - method - got = %v, want %v", method, http.MethodPatch)
+			}
+			if body != tt.wantBody {
+				t.Fatalf("AccountService.Update() - body - got = %v, want %v", body, tt.wantBody)
+			}
+			if _, ok := cache.Get(accountCacheKey("10")); ok {
+				t.Fatalf("AccountService.Update() - cache - got = cached, want invalidated")
+			}
+
+			if tt.wantErr != nil {
+				var conflictErr *VersionConflictError
+				if !errors.Is(err, tt.wantErr) || !errors.Is(err, ErrConflict) || !errors.As(err, &conflictErr) {
+					t.Fatalf("AccountService.Update() error = %v, want %v", err, tt.wantErr)
+				}
+				if conflictErr.ID != "10" || conflictErr.Version != 1 {
+					t.Fatalf("AccountService.Update() - conflict - got = %+v, want account 10 at version 1", conflictErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("AccountService.Update() error = %v", err)
+			}
+			if account.Version == nil || *account.Version != tt.wantVersion {
+				t.Fatalf("AccountService.Update() - version - got = %v, want %v", account.Version, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestAccount_JSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		value interface{}
+	}{
+		{
+			name:  "Test create account request",
+			file:  "account_create_request.json",
+			value: &CreateAccountRequest{},
+		},
+		{
+			name:  "Test fetch account response",
+			file:  "account_fetch_response.json",
+			value: &FetchAccountResponse{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatalf("ReadFile() error = %v", err)
+			}
+
+			// Every field of the examples must be modelled.
+			decoder := json.NewDecoder(bytes.NewReader(want))
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(tt.value); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			got, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+
+			var gotJSON, wantJSON interface{}
+			if err := json.Unmarshal(got, &gotJSON); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if err := json.Unmarshal(want, &wantJSON); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(gotJSON, wantJSON) {
+				t.Fatalf("Marshal() - got = %s, want %s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Token defaults
+const (
+	// defaultTokenExpiryDelta is how long before its expiry a cached token is refreshed.
+	defaultTokenExpiryDelta = 30 * time.Second
+
+	// defaultTokenRefreshTimeout is how long a token request can take.
+	defaultTokenRefreshTimeout = 30 * time.Second
+)
+
+// Authenticator adds credentials to the requests sent to the Form3 API.
+type Authenticator interface {
+	// Authenticate adds the credentials to the request.
+	Authenticate(req *http.Request) error
+
+	// Invalidate discards the credentials used by a request the API rejected with a 401 status code,
+	// so the next call to Authenticate gets new ones.
+	Invalidate(req *http.Request)
+}
+
+// ClientCredentialsAuthenticator authenticates requests with a bearer token obtained through
+// the OAuth2 client credentials flow. The token is cached until shortly before it expires and
+// concurrent callers share a single refresh. It is safe for concurrent use.
+type ClientCredentialsAuthenticator struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       []string
+
+	// HTTPClient is the HTTP client used to call the token endpoint. http.DefaultClient is used if nil.
+	HTTPClient *http.Client
+
+	// ExpiryDelta is how long before its expiry a token is refreshed.
+	ExpiryDelta time.Duration
+
+	// RefreshTimeout is how long a token request can take, whatever the deadline of the callers waiting for it,
+	// so a hanging token endpoint does not block every later request. It defaults to 30 seconds if zero.
+	RefreshTimeout time.Duration
+
+	mu      sync.Mutex
+	token   *oauthToken
+	refresh *tokenRefresh
+
+	// now returns the current time. It is replaced in tests.
+	now func() time.Time
+}
+
+// oauthToken is a cached access token.
+type oauthToken struct {
+	accessToken string
+	expiry      time.Time
+}
+
+// tokenRefresh is a token request in flight, shared by every caller waiting for it.
+type tokenRefresh struct {
+	done  chan struct{}
+	token *oauthToken
+	err   error
+}
+
+// tokenResponse is the body returned by an OAuth2 token endpoint.
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// NewClientCredentialsAuthenticator returns an authenticator requesting tokens from the given token endpoint.
+func NewClientCredentialsAuthenticator(tokenURL, clientID, clientSecret string, scopes ...string) *ClientCredentialsAuthenticator {
+	return &ClientCredentialsAuthenticator{
+		tokenURL:     tokenURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		scopes:       scopes,
+		ExpiryDelta:  defaultTokenExpiryDelta,
+		now:          time.Now,
+	}
+}
+
+// Authenticate adds the bearer token to the Authorization header of the request, requesting a new one if needed.
+func (a *ClientCredentialsAuthenticator) Authenticate(req *http.Request) error {
+	token, err := a.Token(req.Context())
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Invalidate discards the cached token if it is the one used by the given request.
+// Requests sent with an already replaced token do not trigger another refresh.
+func (a *ClientCredentialsAuthenticator) Invalidate(req *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != nil && req.Header.Get("Authorization") == "Bearer "+a.token.accessToken {
+		a.token = nil
+	}
+}
+
+// Token returns a valid access token, requesting a new one if the cached one is missing or about to expire.
+func (a *ClientCredentialsAuthenticator) Token(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	if a.token != nil && a.now().Before(a.token.expiry.Add(-a.ExpiryDelta)) {
+		token := a.token.accessToken
+		a.mu.Unlock()
+		return token, nil
+	}
+
+	// Only the first caller starts a refresh, the rest wait for its result.
+	refresh := a.refresh
+	if refresh == nil {
+		refresh = &tokenRefresh{done: make(chan struct{})}
+		a.refresh = refresh
+
+		// The refresh must not be aborted if the caller that started it gives up, but it has its own timeout.
+		timeout := a.RefreshTimeout
+		if timeout <= 0 {
+			timeout = defaultTokenRefreshTimeout
+		}
+		refreshCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+		go func() {
+			defer cancel()
+			a.doRefresh(refreshCtx, refresh)
+		}()
+	}
+	a.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-refresh.done:
+	}
+
+	if refresh.err != nil {
+		return "", refresh.err
+	}
+	return refresh.token.accessToken, nil
+}
+
+// doRefresh requests a new token and shares the result with every caller waiting for it.
+func (a *ClientCredentialsAuthenticator) doRefresh(ctx context.Context, refresh *tokenRefresh) {
+	token, err := a.requestToken(ctx)
+
+	a.mu.Lock()
+	refresh.token, refresh.err = token, err
+	if err == nil {
+		a.token = token
+	}
+	a.refresh = nil
+	a.mu.Unlock()
+
+	close(refresh.done)
+}
+
+// requestToken requests a new token from the token endpoint.
+func (a *ClientCredentialsAuthenticator) requestToken(ctx context.Context) (*oauthToken, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(a.scopes) > 0 {
+		form.Set("scope", strings.Join(a.scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(a.clientID), url.QueryEscape(a.clientSecret))
+
+	httpClient := a.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	requestedAt := a.now()
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request token: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	var tokenRes tokenResponse
+	if err := json.Unmarshal(body, &tokenRes); err != nil && res.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint failed with status code %d: %s %s", res.StatusCode, tokenRes.Error, tokenRes.ErrorDescription)
+	}
+	if tokenRes.AccessToken == "" {
+		return nil, errors.New("token endpoint returned an empty access token")
+	}
+	if tokenRes.TokenType != "" && !strings.EqualFold(tokenRes.TokenType, "bearer") {
+		return nil, fmt.Errorf("unsupported token type %q", tokenRes.TokenType)
+	}
+
+	// Tokens without expiry are kept until the API rejects them.
+	expiry := requestedAt.Add(100 * 365 * 24 * time.Hour)
+	if tokenRes.ExpiresIn > 0 {
+		expiry = requestedAt.Add(time.Duration(tokenRes.ExpiresIn) * time.Second)
+	}
+
+	return &oauthToken{accessToken: tokenRes.AccessToken, expiry: expiry}, nil
+}
@@ -0,0 +1,210 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeTokenServer is a local OAuth2 token endpoint issuing numbered tokens.
+type fakeTokenServer struct {
+	*httptest.Server
+	requests  int32
+	expiresIn int
+	delay     time.Duration
+}
+
+func newFakeTokenServer(t *testing.T, expiresIn int, delay time.Duration) *fakeTokenServer {
+	fake := &fakeTokenServer{expiresIn: expiresIn, delay: delay}
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok || clientID != "client" || clientSecret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": "invalid_client"}`))
+			return
+		}
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "client_credentials" {
+			t.Errorf("token request - grant_type - got = %v, want %v", r.PostForm.Get("grant_type"), "client_credentials")
+		}
+
+		time.Sleep(fake.delay)
+		n := atomic.AddInt32(&fake.requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(fmt.Sprintf(`{"access_token": "token-%d", "token_type": "Bearer", "expires_in": %d}`, n, fake.expiresIn)))
+	}))
+	return fake
+}
+
+func TestClientCredentialsAuthenticator_Token(t *testing.T) {
+	tokenServer := newFakeTokenServer(t, 3600, 0)
+	defer tokenServer.Close()
+
+	now := time.Now()
+	auth := NewClientCredentialsAuthenticator(tokenServer.URL, "client", "secret", "accounts")
+	auth.now = func() time.Time { return now }
+
+	tests := []struct {
+		name         string
+		advance      time.Duration
+		wantToken    string
+		wantRequests int32
+	}{
+		{name: "Test first token is requested", advance: 0, wantToken: "token-1", wantRequests: 1},
+		{name: "Test token is cached", advance: time.Hour - time.Minute, wantToken: "token-1", wantRequests: 1},
+		{name: "Test token is refreshed before expiring", advance: 45 * time.Second, wantToken: "token-2", wantRequests: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.advance)
+
+			token, err := auth.Token(context.Background())
+			if err != nil {
+				t.Fatalf("ClientCredentialsAuthenticator.Token() error = %v", err)
+			}
+			if token != tt.wantToken {
+				t.Fatalf("ClientCredentialsAuthenticator.Token() - token - got = %v, want %v", token, tt.wantToken)
+			}
+			if got := atomic.LoadInt32(&tokenServer.requests); got != tt.wantRequests {
+				t.Fatalf("ClientCredentialsAuthenticator.Token() - requests - got = %v, want %v", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestClientCredentialsAuthenticator_RefreshTimeout(t *testing.T) {
+	tokenServer := newFakeTokenServer(t, 3600, 200*time.Millisecond)
+	defer tokenServer.Close()
+
+	auth := NewClientCredentialsAuthenticator(tokenServer.URL, "client", "secret")
+	auth.RefreshTimeout = 50 * time.Millisecond
+
+	// The callers have no deadline, so only the refresh timeout stops the hanging token requests, and every
+	// call starts a new refresh instead of joining a timed out one.
+	for i := 0; i < 2; i++ {
+		if _, err := auth.Token(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("ClientCredentialsAuthenticator.Token() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	}
+}
+
+func TestClientCredentialsAuthenticator_InvalidCredentials(t *testing.T) {
+	tokenServer := newFakeTokenServer(t, 3600, 0)
+	defer tokenServer.Close()
+
+	auth := NewClientCredentialsAuthenticator(tokenServer.URL, "client", "wrong")
+
+	_, err := auth.Token(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "invalid_client") {
+		t.Fatalf("ClientCredentialsAuthenticator.Token() error - got = %v, want %v", err, "invalid_client")
+	}
+}
+
+func TestClient_Do_Authenticator(t *testing.T) {
+	t.Run("Test concurrent requests share a single token refresh", func(t *testing.T) {
+		tokenServer := newFakeTokenServer(t, 3600, 50*time.Millisecond)
+		defer tokenServer.Close()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer token-1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"data": {"id": "10"}}`))
+		}))
+		defer server.Close()
+
+		auth := NewClientCredentialsAuthenticator(tokenServer.URL, "client", "secret")
+		client, err := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithAuthenticator(auth))
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var result map[string]interface{}
+				if err := client.Do(context.Background(), http.MethodGet, "organisation/accounts/10", nil, &result); err != nil {
+					t.Errorf("Client.Do() error = %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if got := atomic.LoadInt32(&tokenServer.requests); got != 1 {
+			t.Fatalf("Client.Do() - token requests - got = %v, want %v", got, 1)
+		}
+	})
+
+	t.Run("Test revoked token is refreshed once on 401", func(t *testing.T) {
+		tokenServer := newFakeTokenServer(t, 3600, 0)
+		defer tokenServer.Close()
+
+		var apiRequests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&apiRequests, 1)
+			// Only the second token is accepted.
+			if r.Header.Get("Authorization") != "Bearer token-2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		auth := NewClientCredentialsAuthenticator(tokenServer.URL, "client", "secret")
+		client, err := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithAuthenticator(auth))
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+
+		if err := client.Do(context.Background(), http.MethodDelete, "organisation/accounts/10?version=0", nil, nil); err != nil {
+			t.Fatalf("Client.Do() error = %v", err)
+		}
+		if got := atomic.LoadInt32(&apiRequests); got != 2 {
+			t.Fatalf("Client.Do() - API requests - got = %v, want %v", got, 2)
+		}
+		if got := atomic.LoadInt32(&tokenServer.requests); got != 2 {
+			t.Fatalf("Client.Do() - token requests - got = %v, want %v", got, 2)
+		}
+	})
+}
+
+func TestClient_SignerWithAuthenticator(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	auth := NewClientCredentialsAuthenticator(server.URL, "client", "secret")
+	signer := signerFunc(func(req *http.Request, body []byte) error {
+		req.Header.Set("Authorization", "Signature keyId=\"key\"")
+		return nil
+	})
+
+	_, err := NewClient(WithBaseURL(server.URL), WithAuthenticator(auth), WithSigner(signer))
+	if !errors.Is(err, ErrSignerWithAuthenticator) {
+		t.Fatalf("NewClient() error = %v, want %v", err, ErrSignerWithAuthenticator)
+	}
+
+	serverUrl, _ := url.Parse(server.URL)
+	client := &Client{BaseURL: serverUrl, client: server.Client(), Authenticator: auth, Signer: signer}
+	if err := client.Do(context.Background(), http.MethodDelete, "organisation/accounts/10", nil, nil); !errors.Is(err, ErrSignerWithAuthenticator) {
+		t.Fatalf("Client.Do() error = %v, want %v", err, ErrSignerWithAuthenticator)
+	}
+	if requests != 0 {
+		t.Fatalf("Client.Do() - requests - got = %v, want none", requests)
+	}
+}
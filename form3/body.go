@@ -0,0 +1,62 @@
+package form3
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// defaultMaxResponseSize is the maximum size of a response body when none is set with WithMaxResponseSize.
+const defaultMaxResponseSize = 10 << 20
+
+// ErrResponseTooLarge is returned when a response body is larger than the client MaxResponseSize.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// maxResponseSize returns the maximum size of a response body allowed by the client.
+func (c *Client) maxResponseSize() int64 {
+	if c.MaxResponseSize <= 0 {
+		return defaultMaxResponseSize
+	}
+	return c.MaxResponseSize
+}
+
+// limitedReader reads from r until more than limit bytes are read, then fails with ErrResponseTooLarge.
+// Unlike io.LimitReader, it tells truncated bodies from bodies of exactly limit bytes.
+type limitedReader struct {
+	r     io.Reader
+	limit int64
+	n     int64
+	err   error
+}
+
+func newLimitedReader(r io.Reader, limit int64) *limitedReader {
+	return &limitedReader{r: r, limit: limit, n: limit}
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+
+	// We read one more byte than allowed to know whether the body goes over the limit.
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+
+	n, err := l.r.Read(p)
+	if int64(n) <= l.n {
+		l.n -= int64(n)
+		l.err = err
+		return n, err
+	}
+
+	n = int(l.n)
+	l.n = 0
+	l.err = tooLargeError(l.limit)
+	return n, l.err
+}
+
+// tooLargeError returns an ErrResponseTooLarge error telling the limit.
+func tooLargeError(limit int64) error {
+	return fmt.Errorf("%w: limit is %d bytes", ErrResponseTooLarge, limit)
+}
@@ -0,0 +1,169 @@
+package form3
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestLimitedReader(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		limit    int64
+		wantBody string
+		wantErr  error
+	}{
+		{name: "Test body smaller than the limit", body: "1234", limit: 5, wantBody: "1234"},
+		{name: "Test body of exactly the limit", body: "12345", limit: 5, wantBody: "12345"},
+		{name: "Test body larger than the limit", body: "123456", limit: 5, wantBody: "12345", wantErr: ErrResponseTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := io.ReadAll(newLimitedReader(strings.NewReader(tt.body), tt.limit))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("limitedReader.Read() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.wantBody {
+				t.Fatalf("limitedReader.Read() - body - got = %v, want %v", string(got), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestClient_decodeBody_MaxResponseSize(t *testing.T) {
+	large := `{"data": {"id": "` + strings.Repeat("1", 100) + `"}}`
+
+	tests := []struct {
+		name          string
+		statusCode    int
+		contentLength int64
+		body          string
+		wantErr       error
+	}{
+		{
+			name:          "Test body within the limit",
+			statusCode:    http.StatusOK,
+			contentLength: -1,
+			body:          `{"data": {"id": "10"}}`,
+		},
+		{
+			name:          "Test body larger than its announced length",
+			statusCode:    http.StatusOK,
+			contentLength: -1,
+			body:          large,
+			wantErr:       ErrResponseTooLarge,
+		},
+		{
+			name:          "Test announced length larger than the limit",
+			statusCode:    http.StatusOK,
+			contentLength: int64(len(large)),
+			body:          large,
+			wantErr:       ErrResponseTooLarge,
+		},
+		{
+			name:          "Test error body larger than the limit keeps the status code",
+			statusCode:    http.StatusBadGateway,
+			contentLength: -1,
+			body:          strings.Repeat("<html>", 20),
+			wantErr:       ErrServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &http.Response{
+				StatusCode:    tt.statusCode,
+				ContentLength: tt.contentLength,
+				Body:          io.NopCloser(strings.NewReader(tt.body)),
+			}
+
+			var result FetchAccountResponse
+			err := (&Client{MaxResponseSize: 64}).decodeBody(res, &result)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Client.decodeBody() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// listBody returns a list response with n accounts.
+func listBody(b *testing.B, n int) []byte {
+	accounts := make([]Account, n)
+	for i := range accounts {
+		accounts[i] = Account{
+			ID:             fmt.Sprintf("ad27e265-9605-4b4b-a0e5-%012d", i),
+			OrganisationID: "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+			Type:           "accounts",
+			Version:        ToPointer(int64(0)),
+			Attributes: &AccountAttributes{
+				AccountNumber: "41426819",
+				BankID:        "400300",
+				BankIDCode:    "GBDSC",
+				Bic:           "NWBKGB22",
+				Country:       ToPointer("GB"),
+				Iban:          "GB11NWBK40030041426819",
+				Name:          []string{"Samantha Holder"},
+			},
+		}
+	}
+
+	body, err := json.Marshal(Form3BodyResponse[[]Account]{Data: accounts})
+	if err != nil {
+		b.Fatalf("json.Marshal() error = %v", err)
+	}
+	return body
+}
+
+// BenchmarkClient_decodeBody compares decoding the body as it is read with reading it at once before decoding it,
+// as previous versions did. Both are bounded by the same limit, so oversized bodies are only read up to it either way.
+func BenchmarkClient_decodeBody(b *testing.B) {
+	benchmarks := []struct {
+		name  string
+		body  []byte
+		limit int64
+	}{
+		{name: "list page", body: listBody(b, 100), limit: defaultMaxResponseSize},
+		{name: "large list page", body: listBody(b, 5000), limit: defaultMaxResponseSize},
+		{name: "oversized body", body: listBody(b, 10000), limit: 1 << 20},
+	}
+
+	for _, bm := range benchmarks {
+		c := &Client{MaxResponseSize: bm.limit}
+
+		b.Run(bm.name+"/stream", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				res := &http.Response{StatusCode: http.StatusOK, ContentLength: -1, Body: io.NopCloser(bytes.NewReader(bm.body))}
+				var result Form3BodyResponse[[]Account]
+				if err := c.decodeBody(res, &result); err != nil && !errors.Is(err, ErrResponseTooLarge) {
+					b.Fatalf("Client.decodeBody() error = %v", err)
+				}
+			}
+		})
+
+		b.Run(bm.name+"/read all", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				res := &http.Response{StatusCode: http.StatusOK, ContentLength: -1, Body: io.NopCloser(bytes.NewReader(bm.body))}
+				var result Form3BodyResponse[[]Account]
+				resBody, err := io.ReadAll(newLimitedReader(res.Body, bm.limit))
+				if errors.Is(err, ErrResponseTooLarge) {
+					continue
+				}
+				if err != nil {
+					b.Fatalf("io.ReadAll() error = %v", err)
+				}
+				if err := json.Unmarshal(resBody, &result); err != nil {
+					b.Fatalf("json.Unmarshal() error = %v", err)
+				}
+			}
+		})
+	}
+}
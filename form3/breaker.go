@@ -0,0 +1,299 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Circuit breaker defaults
+const (
+	defaultCircuitFailureThreshold = 5
+	defaultCircuitOpenTimeout      = 30 * time.Second
+	defaultCircuitHalfOpenRequests = 1
+)
+
+// ErrCircuitOpen is returned without sending the request when the circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of a circuit.
+type CircuitState int
+
+// Circuit states
+const (
+	// CircuitClosed lets every request through.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen fails every request with ErrCircuitOpen.
+	CircuitOpen
+
+	// CircuitHalfOpen lets a few requests through to check whether the Form3 API recovered.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitScope tells which requests share a circuit.
+type CircuitScope int
+
+// Circuit scopes
+const (
+	// CircuitPerHost shares a circuit between all the requests sent to the same host.
+	CircuitPerHost CircuitScope = iota
+
+	// CircuitPerOperation has a circuit per operation, such as "accounts.fetch".
+	CircuitPerOperation
+)
+
+// CircuitBreaker stops sending requests to the Form3 API for a while after consecutive failures, so an outage does
+// not pile up requests waiting on timeouts. Server errors and transport errors, such as timeouts, are failures,
+// while client errors (4xx) are not. It is safe for concurrent use and can be shared between clients.
+type CircuitBreaker struct {
+	// Scope tells which requests share a circuit.
+	Scope CircuitScope
+
+	// FailureThreshold is the number of consecutive failures which opens a circuit.
+	FailureThreshold int
+
+	// OpenTimeout is how long a circuit stays open before letting requests through again.
+	OpenTimeout time.Duration
+
+	// HalfOpenRequests is the number of requests let through at the same time by a half-open circuit.
+	// A successful one closes the circuit, while a failed one opens it again.
+	HalfOpenRequests int
+
+	// OnStateChange is called every time a circuit changes its state, for example to raise alerts.
+	// The name of the circuit is the host or the operation depending on the Scope.
+	OnStateChange func(name string, from, to CircuitState)
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+
+	// now returns the current time. It is replaced in tests.
+	now func() time.Time
+}
+
+// circuit is the state of the requests sharing a circuit.
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probes   int
+
+	// generation changes with the state, so outcomes of requests allowed in a previous state are ignored.
+	generation uint64
+}
+
+// setState changes the state of the circuit and starts a new generation if the state is a different one.
+func (c *circuit) setState(state CircuitState) {
+	if c.state != state {
+		c.state = state
+		c.generation++
+	}
+}
+
+// circuitTicket identifies a request allowed through a circuit, in the state it was allowed in.
+type circuitTicket struct {
+	name       string
+	generation uint64
+}
+
+// NewCircuitBreaker returns a circuit breaker with the given scope and suitable defaults: circuits open after
+// 5 consecutive failures and let a request through again after 30 seconds.
+func NewCircuitBreaker(scope CircuitScope) *CircuitBreaker {
+	return &CircuitBreaker{
+		Scope:            scope,
+		FailureThreshold: defaultCircuitFailureThreshold,
+		OpenTimeout:      defaultCircuitOpenTimeout,
+		HalfOpenRequests: defaultCircuitHalfOpenRequests,
+	}
+}
+
+// State returns the current state of the circuit with the given name.
+func (cb *CircuitBreaker) State(name string) CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	c, ok := cb.circuits[name]
+	if !ok {
+		return CircuitClosed
+	}
+	if c.state == CircuitOpen && cb.currentTime().Sub(c.openedAt) >= cb.OpenTimeout {
+		return CircuitHalfOpen
+	}
+	return c.state
+}
+
+// circuitName returns the name of the circuit of a request sent to the given host.
+func (cb *CircuitBreaker) circuitName(r *Request, host string) string {
+	if cb.Scope == CircuitPerOperation {
+		return metricsOperation(r)
+	}
+	return host
+}
+
+// allow reports whether a request can be sent through the named circuit. If it returns true, the outcome of the
+// request must be recorded with done, using the returned ticket.
+func (cb *CircuitBreaker) allow(name string) (circuitTicket, bool) {
+	cb.mu.Lock()
+	c := cb.circuit(name)
+	from := c.state
+
+	if c.state == CircuitOpen {
+		if cb.currentTime().Sub(c.openedAt) < cb.OpenTimeout {
+			cb.mu.Unlock()
+			return circuitTicket{}, false
+		}
+		c.setState(CircuitHalfOpen)
+		c.probes = 0
+	}
+
+	allowed := true
+	if c.state == CircuitHalfOpen {
+		allowed = c.probes < max(cb.HalfOpenRequests, 1)
+		if allowed {
+			c.probes++
+		}
+	}
+	to := c.state
+	ticket := circuitTicket{name: name, generation: c.generation}
+	cb.mu.Unlock()
+
+	cb.notify(name, from, to)
+	return ticket, allowed
+}
+
+// done records the outcome of a request allowed through a circuit. Outcomes which are neither a success nor
+// a failure, such as cancelled requests, only free the place of the request in a half-open circuit. Outcomes of
+// requests allowed before the circuit last changed its state are ignored: a request allowed while the circuit was
+// closed tells nothing about the recovery checked by the probes of the half-open circuit.
+func (cb *CircuitBreaker) done(ticket circuitTicket, outcome circuitOutcome) {
+	cb.mu.Lock()
+	c := cb.circuit(ticket.name)
+	from := c.state
+
+	if ticket.generation != c.generation {
+		cb.mu.Unlock()
+		return
+	}
+
+	if c.state == CircuitHalfOpen && c.probes > 0 {
+		c.probes--
+	}
+
+	switch outcome {
+	case circuitSuccess:
+		c.failures = 0
+		c.setState(CircuitClosed)
+	case circuitFailure:
+		c.failures++
+		if c.state == CircuitHalfOpen || c.state == CircuitClosed && c.failures >= max(cb.FailureThreshold, 1) {
+			c.setState(CircuitOpen)
+			c.openedAt = cb.currentTime()
+		}
+	}
+	to := c.state
+	cb.mu.Unlock()
+
+	cb.notify(ticket.name, from, to)
+}
+
+// circuit returns the named circuit, creating it if needed. It must be called with the lock held.
+func (cb *CircuitBreaker) circuit(name string) *circuit {
+	if cb.circuits == nil {
+		cb.circuits = map[string]*circuit{}
+	}
+
+	c, ok := cb.circuits[name]
+	if !ok {
+		c = &circuit{}
+		cb.circuits[name] = c
+	}
+	return c
+}
+
+// notify calls OnStateChange if the state of the named circuit changed.
+func (cb *CircuitBreaker) notify(name string, from, to CircuitState) {
+	if from != to && cb.OnStateChange != nil {
+		cb.OnStateChange(name, from, to)
+	}
+}
+
+func (cb *CircuitBreaker) currentTime() time.Time {
+	if cb.now == nil {
+		return time.Now()
+	}
+	return cb.now()
+}
+
+// allowCircuit checks that the client circuit breaker, if any, lets the request through, and returns the ticket
+// of the request. It is checked before anything else, so requests failing fast do not wait for the rate limiter.
+func (c *Client) allowCircuit(r *Request) (circuitTicket, error) {
+	if c.CircuitBreaker == nil {
+		return circuitTicket{}, nil
+	}
+
+	// Requests are always sent to the host of the base URL.
+	name := c.CircuitBreaker.circuitName(r, c.BaseURL.Host)
+	ticket, allowed := c.CircuitBreaker.allow(name)
+	if !allowed {
+		return ticket, fmt.Errorf("%w for %s", ErrCircuitOpen, name)
+	}
+	return ticket, nil
+}
+
+// releaseCircuit frees the place of a request allowed through its circuit which was not sent after all.
+func (c *Client) releaseCircuit(ticket circuitTicket) {
+	if c.CircuitBreaker != nil {
+		c.CircuitBreaker.done(ticket, circuitIgnored)
+	}
+}
+
+// doneCircuit records the outcome of an attempt in the client circuit breaker, if any.
+func (c *Client) doneCircuit(ctx context.Context, ticket circuitTicket, res *http.Response, err error) {
+	if c.CircuitBreaker == nil {
+		return
+	}
+	c.CircuitBreaker.done(ticket, outcomeOf(ctx, res, err))
+}
+
+// circuitOutcome is the outcome of a request as seen by the circuit breaker.
+type circuitOutcome int
+
+const (
+	circuitSuccess circuitOutcome = iota
+	circuitFailure
+	circuitIgnored
+)
+
+// outcomeOf classifies the outcome of an attempt. Only server errors and transport errors, including client side
+// timeouts, are failures, as client errors mean that the Form3 API is working. Requests whose context is cancelled
+// or expires tell nothing about it: a caller with a tight deadline must not open the circuit of everyone else.
+func outcomeOf(ctx context.Context, res *http.Response, err error) circuitOutcome {
+	switch {
+	case err != nil && ctx.Err() != nil:
+		return circuitIgnored
+	case err != nil && errors.Is(err, ErrTimeout):
+		return circuitFailure
+	case err != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)):
+		return circuitIgnored
+	case err != nil:
+		return circuitFailure
+	case res.StatusCode >= 500:
+		return circuitFailure
+	}
+	return circuitSuccess
+}
@@ -0,0 +1,241 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_Do_CircuitBreaker(t *testing.T) {
+	var status, requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+		w.Write([]byte(`{"data": {"id": "10"}}`))
+	}))
+	defer server.Close()
+
+	now := time.Unix(1683000000, 0)
+	var changes []string
+	breaker := NewCircuitBreaker(CircuitPerOperation)
+	breaker.FailureThreshold = 2
+	breaker.OpenTimeout = time.Minute
+	breaker.now = func() time.Time { return now }
+	breaker.OnStateChange = func(name string, from, to CircuitState) {
+		changes = append(changes, fmt.Sprintf("%s %s->%s", name, from, to))
+	}
+
+	client, err := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithCircuitBreaker(breaker))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		status      int32
+		advance     time.Duration
+		wantErr     error
+		wantSent    bool
+		wantState   CircuitState
+		wantChanges []string
+	}{
+		{name: "Test client errors are not failures", status: 404, wantErr: ErrNotFound, wantSent: true, wantState: CircuitClosed},
+		{name: "Test client errors are not failures again", status: 404, wantErr: ErrNotFound, wantSent: true, wantState: CircuitClosed},
+		{name: "Test first server error", status: 503, wantErr: ErrServer, wantSent: true, wantState: CircuitClosed},
+		{
+			name: "Test consecutive server errors open the circuit", status: 503, wantErr: ErrServer, wantSent: true, wantState: CircuitOpen,
+			wantChanges: []string{"accounts.fetch closed->open"},
+		},
+		{name: "Test open circuit fails fast", status: 200, wantErr: ErrCircuitOpen, wantState: CircuitOpen},
+		{
+			name: "Test failed probe opens the circuit again", status: 500, advance: time.Minute, wantErr: ErrServer, wantSent: true, wantState: CircuitOpen,
+			wantChanges: []string{"accounts.fetch open->half-open", "accounts.fetch half-open->open"},
+		},
+		{
+			name: "Test successful probe closes the circuit", status: 200, advance: time.Minute, wantSent: true, wantState: CircuitClosed,
+			wantChanges: []string{"accounts.fetch open->half-open", "accounts.fetch half-open->closed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&status, tt.status)
+			atomic.StoreInt32(&requests, 0)
+			changes = nil
+			now = now.Add(tt.advance)
+
+			_, _, err := client.Account.Fetch(context.Background(), "10")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AccountService.Fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if sent := atomic.LoadInt32(&requests) > 0; sent != tt.wantSent {
+				t.Fatalf("AccountService.Fetch() - sent - got = %v, want %v", sent, tt.wantSent)
+			}
+			if state := breaker.State(OperationFetchAccount); state != tt.wantState {
+				t.Fatalf("CircuitBreaker.State() - got = %v, want %v", state, tt.wantState)
+			}
+			if !reflect.DeepEqual(changes, tt.wantChanges) {
+				t.Fatalf("CircuitBreaker.OnStateChange - got = %v, want %v", changes, tt.wantChanges)
+			}
+		})
+	}
+
+	// Other operations have their own circuit.
+	if state := breaker.State(OperationDeleteAccount); state != CircuitClosed {
+		t.Fatalf("CircuitBreaker.State() - got = %v, want %v", state, CircuitClosed)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenRequests(t *testing.T) {
+	now := time.Unix(1683000000, 0)
+	breaker := NewCircuitBreaker(CircuitPerHost)
+	breaker.FailureThreshold = 1
+	breaker.now = func() time.Time { return now }
+
+	ticket, _ := breaker.allow("api.form3.tech")
+	breaker.done(ticket, circuitFailure)
+	now = now.Add(breaker.OpenTimeout)
+
+	var probe circuitTicket
+	tests := []struct {
+		name string
+		call func() bool
+		want bool
+	}{
+		{
+			name: "Test first probe is allowed",
+			call: func() bool {
+				var allowed bool
+				probe, allowed = breaker.allow("api.form3.tech")
+				return allowed
+			},
+			want: true,
+		},
+		{name: "Test concurrent probe is rejected", call: func() bool { _, allowed := breaker.allow("api.form3.tech"); return allowed }, want: false},
+		{
+			name: "Test cancelled probe frees its place",
+			call: func() bool {
+				breaker.done(probe, circuitIgnored)
+				_, allowed := breaker.allow("api.form3.tech")
+				return allowed
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.call(); got != tt.want {
+				t.Fatalf("CircuitBreaker.allow() - got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker_StaleRequests(t *testing.T) {
+	tests := []struct {
+		name    string
+		outcome circuitOutcome
+	}{
+		{name: "Test request allowed while closed does not close a half-open circuit", outcome: circuitSuccess},
+		{name: "Test request allowed while closed does not open a half-open circuit", outcome: circuitFailure},
+		{name: "Test request allowed while closed does not free the place of a probe", outcome: circuitIgnored},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Unix(1683000000, 0)
+			breaker := NewCircuitBreaker(CircuitPerHost)
+			breaker.FailureThreshold = 1
+			breaker.now = func() time.Time { return now }
+
+			// A is allowed while closed, B fails and opens the circuit, then C probes it once half-open.
+			a, _ := breaker.allow("api.form3.tech")
+			b, _ := breaker.allow("api.form3.tech")
+			breaker.done(b, circuitFailure)
+			now = now.Add(breaker.OpenTimeout)
+			if _, allowed := breaker.allow("api.form3.tech"); !allowed {
+				t.Fatalf("CircuitBreaker.allow() - probe - got = false, want true")
+			}
+
+			breaker.done(a, tt.outcome)
+
+			if state := breaker.State("api.form3.tech"); state != CircuitHalfOpen {
+				t.Fatalf("CircuitBreaker.State() - got = %v, want %v", state, CircuitHalfOpen)
+			}
+			if _, allowed := breaker.allow("api.form3.tech"); allowed {
+				t.Fatalf("CircuitBreaker.allow() - second probe - got = true, want false")
+			}
+		})
+	}
+}
+
+func TestOutcomeOf(t *testing.T) {
+	expired, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		res  *http.Response
+		err  error
+		want circuitOutcome
+	}{
+		{name: "Test success", ctx: context.Background(), res: &http.Response{StatusCode: 200}, want: circuitSuccess},
+		{name: "Test client error", ctx: context.Background(), res: &http.Response{StatusCode: 404}, want: circuitSuccess},
+		{name: "Test server error", ctx: context.Background(), res: &http.Response{StatusCode: 503}, want: circuitFailure},
+		{name: "Test transport error", ctx: context.Background(), err: errors.New("connection refused"), want: circuitFailure},
+		{
+			name: "Test client side timeout",
+			ctx:  context.Background(),
+			err:  &TimeoutError{Phase: TimeoutPhaseHeaders, Err: context.DeadlineExceeded},
+			want: circuitFailure,
+		},
+		{name: "Test cancelled context", ctx: context.Background(), err: context.Canceled, want: circuitIgnored},
+		{name: "Test expired context of the caller", ctx: expired, err: errors.New("context deadline exceeded"), want: circuitIgnored},
+		{name: "Test deadline of the caller", ctx: context.Background(), err: fmt.Errorf("send: %w", context.DeadlineExceeded), want: circuitIgnored},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := outcomeOf(tt.ctx, tt.res, tt.err); got != tt.want {
+				t.Fatalf("outcomeOf() - got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Do_CircuitBreaker_RateLimiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	breaker := NewCircuitBreaker(CircuitPerHost)
+	breaker.FailureThreshold = 1
+
+	client, err := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithCircuitBreaker(breaker), WithRateLimiter(NewRateLimiter(2, 1)))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	// The first request opens the circuit and takes the only token.
+	client.Account.Fetch(context.Background(), "10")
+
+	// Requests failing fast do not wait for tokens.
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, _, err := client.Account.Fetch(context.Background(), "10"); !errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("AccountService.Fetch() error = %v, want %v", err, ErrCircuitOpen)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("AccountService.Fetch() - duration - got = %v, want no rate limiter wait", elapsed)
+	}
+}
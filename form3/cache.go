@@ -0,0 +1,250 @@
+package form3
+
+import (
+	"container/list"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultCacheSize is the number of entries kept by the default cache.
+const defaultCacheSize = 1000
+
+// Cache stores resources fetched from the Form3 API, so they are not fetched again while fresh.
+// Implementations must be safe for concurrent use. They can evict entries at any time.
+type Cache interface {
+	// Get returns the entry stored with the given key, if any, even if it expired.
+	Get(key string) (CacheEntry, bool)
+
+	// Set stores the entry with the given key.
+	Set(key string, entry CacheEntry)
+
+	// Delete removes the entry stored with the given key, if any.
+	Delete(key string)
+}
+
+// CacheEntry is a resource stored in a Cache.
+type CacheEntry struct {
+	// Value is the JSON encoded resource.
+	Value []byte
+
+	// ETag is the entity tag of the resource, used to revalidate it once expired, if the Form3 API sent one.
+	ETag string
+
+	// Expires is when the resource must be revalidated or fetched again.
+	Expires time.Time
+}
+
+// LRUCache is an in-memory Cache which evicts the least recently used entries when full.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+// lruItem is an element of the LRUCache order list.
+type lruItem struct {
+	key   string
+	entry CacheEntry
+}
+
+// NewLRUCache returns an in-memory cache holding up to capacity entries.
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRUCache{capacity: capacity, entries: map[string]*list.Element{}, order: list.New()}
+}
+
+// Get implements Cache.
+func (c *LRUCache) Get(key string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return CacheEntry{}, false
+	}
+
+	c.order.MoveToFront(element)
+	return element.Value.(*lruItem).entry, true
+}
+
+// Set implements Cache.
+func (c *LRUCache) Set(key string, entry CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*lruItem).entry = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruItem{key: key, entry: entry})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruItem).key)
+	}
+}
+
+// Delete implements Cache.
+func (c *LRUCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+}
+
+// invalidate removes the resource with the given key from the client cache, if any. Fetches of the resource in
+// flight will not store it afterwards.
+func (c *Client) invalidate(key string) {
+	if c.Cache == nil {
+		return
+	}
+
+	c.cacheGenerations.mu.Lock()
+	defer c.cacheGenerations.mu.Unlock()
+
+	if state, ok := c.cacheGenerations.keys[key]; ok {
+		state.generation++
+	}
+	c.Cache.Delete(key)
+}
+
+// cacheGenerations counts the invalidations of the cache keys being fetched, so a fetch started before an
+// invalidation does not store the stale resource it got. The zero value is ready to use.
+type cacheGenerations struct {
+	mu   sync.Mutex
+	keys map[string]*cacheGeneration
+}
+
+// cacheGeneration is the state of a key being fetched.
+type cacheGeneration struct {
+	generation uint64
+	fetches    int
+}
+
+// begin records that the resource with the given key is being fetched, and returns the generation to give to set.
+// end must be called once the fetch is over.
+func (g *cacheGenerations) begin(key string) uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.keys == nil {
+		g.keys = map[string]*cacheGeneration{}
+	}
+
+	state, ok := g.keys[key]
+	if !ok {
+		state = &cacheGeneration{}
+		g.keys[key] = state
+	}
+	state.fetches++
+	return state.generation
+}
+
+// end records that a fetch of the resource with the given key is over.
+func (g *cacheGenerations) end(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	state := g.keys[key]
+	state.fetches--
+	if state.fetches == 0 {
+		delete(g.keys, key)
+	}
+}
+
+// setCache stores the fetched resource in the client cache, unless it was invalidated since the fetch began with
+// the given generation.
+func (c *Client) setCache(key string, generation uint64, entry CacheEntry) {
+	c.cacheGenerations.mu.Lock()
+	defer c.cacheGenerations.mu.Unlock()
+
+	if state, ok := c.cacheGenerations.keys[key]; ok && state.generation != generation {
+		return
+	}
+	c.Cache.Set(key, entry)
+}
+
+// fetchGroup coalesces concurrent fetches of the same resource into a single request.
+// The zero value is ready to use.
+type fetchGroup struct {
+	mu    sync.Mutex
+	calls map[string]*fetchCall
+}
+
+// fetchCall is a fetch shared by several callers.
+type fetchCall struct {
+	done    chan struct{}
+	waiters int
+	cancel  context.CancelFunc
+
+	value []byte
+	res   *Response
+	err   error
+}
+
+// do calls fetch, unless a call with the same key is in flight, in which case it waits for its result instead.
+// The shared call is only cancelled when every caller waiting for it has given up.
+func (g *fetchGroup) do(ctx context.Context, key string, fetch func(ctx context.Context) ([]byte, *Response, error)) ([]byte, *Response, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*fetchCall{}
+	}
+
+	call, ok := g.calls[key]
+	if ok {
+		call.waiters++
+	} else {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &fetchCall{done: make(chan struct{}), waiters: 1, cancel: cancel}
+		g.calls[key] = call
+
+		go func() {
+			defer cancel()
+			call.value, call.res, call.err = fetch(callCtx)
+
+			g.mu.Lock()
+			g.forget(key, call)
+			g.mu.Unlock()
+			close(call.done)
+		}()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.res, call.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			// Later callers must not join a cancelled call.
+			g.forget(key, call)
+			call.cancel()
+		}
+		g.mu.Unlock()
+		return nil, nil, ctx.Err()
+	}
+}
+
+// forget removes the call from the calls in flight. It must be called with the lock held.
+func (g *fetchGroup) forget(key string, call *fetchCall) {
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
+
+// isConditional reports whether the request headers make it a conditional request, which the Form3 API can answer
+// with a 304 status code.
+func isConditional(header http.Header) bool {
+	return header.Get("If-None-Match") != "" || header.Get("If-Modified-Since") != ""
+}
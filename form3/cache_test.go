@@ -0,0 +1,269 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestLRUCache(t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Set("a", CacheEntry{ETag: "a"})
+	cache.Set("b", CacheEntry{ETag: "b"})
+	cache.Get("a")
+	cache.Set("c", CacheEntry{ETag: "c"})
+	cache.Set("d", CacheEntry{ETag: "d"})
+	cache.Delete("d")
+
+	tests := []struct {
+		key    string
+		wantOK bool
+	}{
+		{key: "a", wantOK: false},
+		{key: "b", wantOK: false},
+		{key: "c", wantOK: true},
+		{key: "d", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		entry, ok := cache.Get(tt.key)
+		if ok != tt.wantOK {
+			t.Fatalf("LRUCache.Get(%q) - ok - got = %v, want %v", tt.key, ok, tt.wantOK)
+		}
+		if ok && entry.ETag != tt.key {
+			t.Fatalf("LRUCache.Get(%q) - ETag - got = %v, want %v", tt.key, entry.ETag, tt.key)
+		}
+	}
+}
+
+func TestAccountService_Fetch_Coalescing(t *testing.T) {
+	const callers = 5
+
+	var requests int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		w.Write([]byte(`{"data": {"id": "10", "version": 0}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	accounts := make([]*Account, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			account, _, err := client.Account.Fetch(context.Background(), "10")
+			if err != nil {
+				t.Errorf("AccountService.Fetch() error = %v", err)
+			}
+			accounts[i] = account
+		}(i)
+	}
+
+	// Callers cannot be synchronised with the request, so we give them time to join it.
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if requests != 1 {
+		t.Fatalf("AccountService.Fetch() - requests - got = %v, want %v", requests, 1)
+	}
+	if accounts[0] == nil || accounts[0] == accounts[1] || accounts[0].ID != "10" || accounts[1].ID != "10" {
+		t.Fatalf("AccountService.Fetch() - every caller must get its own copy of the account, got %v and %v", accounts[0], accounts[1])
+	}
+}
+
+func TestAccountService_Fetch_Cache(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.Header.Get("If-None-Match"))
+		switch {
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		case r.Header.Get("If-None-Match") == `"v0"`:
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			w.Header().Set("ETag", `"v0"`)
+			w.Write([]byte(`{"data": {"id": "10", "version": 0}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithCache(nil, time.Hour))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	expire := func() {
+		entry, _ := client.Cache.Get(accountCacheKey("10"))
+		entry.Expires = time.Now()
+		client.Cache.Set(accountCacheKey("10"), entry)
+	}
+
+	tests := []struct {
+		name          string
+		before        func()
+		wantRequest   string
+		wantFromCache bool
+	}{
+		{name: "Test account is fetched", wantRequest: "GET "},
+		{name: "Test fresh account is returned from the cache", wantFromCache: true},
+		{name: "Test expired account is revalidated", before: expire, wantRequest: `GET "v0"`, wantFromCache: true},
+		{name: "Test revalidated account is fresh", wantFromCache: true},
+		{
+			name: "Test deleted account is fetched again",
+			before: func() {
+				if _, err := client.Account.Delete(context.Background(), "10", 0); err != nil {
+					t.Fatalf("AccountService.Delete() error = %v", err)
+				}
+				requests = requests[1:]
+			},
+			wantRequest: "GET ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = nil
+			if tt.before != nil {
+				tt.before()
+			}
+
+			account, res, err := client.Account.Fetch(context.Background(), "10")
+			if err != nil {
+				t.Fatalf("AccountService.Fetch() error = %v", err)
+			}
+			if account.ID != "10" {
+				t.Fatalf("AccountService.Fetch() - ID - got = %v, want %v", account.ID, "10")
+			}
+			if res.FromCache != tt.wantFromCache {
+				t.Fatalf("AccountService.Fetch() - FromCache - got = %v, want %v", res.FromCache, tt.wantFromCache)
+			}
+
+			var wantRequests []string
+			if tt.wantRequest != "" {
+				wantRequests = []string{tt.wantRequest}
+			}
+			if len(requests) != len(wantRequests) || len(requests) > 0 && requests[0] != wantRequests[0] {
+				t.Fatalf("AccountService.Fetch() - requests - got = %q, want %q", requests, wantRequests)
+			}
+		})
+	}
+}
+
+func TestAccountService_Fetch_Revalidation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v0"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v0"`)
+		w.Write([]byte(`{"data": {"id": "10", "version": 0}}`))
+	}))
+	defer server.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	client, err := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithCache(nil, time.Hour), WithTracerProvider(provider))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	var middlewareErrs []error
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			res, err := next(ctx, req)
+			middlewareErrs = append(middlewareErrs, err)
+			return res, err
+		}
+	})
+
+	if _, _, err := client.Account.Fetch(context.Background(), "10"); err != nil {
+		t.Fatalf("AccountService.Fetch() error = %v", err)
+	}
+
+	// The cached account expires, so it is revalidated.
+	entry, _ := client.Cache.Get(accountCacheKey("10"))
+	entry.Expires = time.Now()
+	client.Cache.Set(accountCacheKey("10"), entry)
+	exporter.Reset()
+
+	account, res, err := client.Account.Fetch(context.Background(), "10")
+	if err != nil {
+		t.Fatalf("AccountService.Fetch() error = %v", err)
+	}
+	if account.ID != "10" || res.StatusCode != http.StatusNotModified || !res.FromCache {
+		t.Fatalf("AccountService.Fetch() - got = %v, %+v, want the cached account", account.ID, res)
+	}
+	if len(middlewareErrs) != 2 || middlewareErrs[1] != nil {
+		t.Fatalf("Client.Use() - errors - got = %v, want no error for the revalidation", middlewareErrs)
+	}
+
+	for _, span := range exporter.GetSpans() {
+		if span.Status.Code == codes.Error || len(span.Events) > 0 {
+			t.Fatalf("span %s - status - got = %v, want no error", span.Name, span.Status)
+		}
+	}
+	if spans := exporter.GetSpans(); len(spans) != 2 {
+		t.Fatalf("spans - got = %v, want %v", len(spans), 2)
+	}
+}
+
+func TestAccountService_Fetch_CacheInvalidation(t *testing.T) {
+	fetching, release := make(chan struct{}), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		close(fetching)
+		<-release
+		w.Write([]byte(`{"data": {"id": "10", "version": 0}}`))
+	}))
+	defer server.Close()
+
+	cache := NewLRUCache(10)
+	client, err := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithCache(cache, time.Minute))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	done := make(chan error)
+	go func() {
+		_, _, err := client.Account.Fetch(context.Background(), "10")
+		done <- err
+	}()
+
+	// The account is deleted while it is being fetched.
+	<-fetching
+	if _, err := client.Account.Delete(context.Background(), "10", 0); err != nil {
+		t.Fatalf("AccountService.Delete() error = %v", err)
+	}
+	close(release)
+
+	if err := <-done; err != nil {
+		t.Fatalf("AccountService.Fetch() error = %v", err)
+	}
+	if _, ok := cache.Get(accountCacheKey("10")); ok {
+		t.Fatalf("AccountService.Fetch() - cache - got = cached, want the deleted account not cached")
+	}
+	if len(client.cacheGenerations.keys) != 0 {
+		t.Fatalf("cacheGenerations - keys - got = %v, want none once the fetches are over", client.cacheGenerations.keys)
+	}
+}
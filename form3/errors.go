@@ -0,0 +1,47 @@
+package form3
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Sentinel errors matched by errors.Is on a *Form3APIError, depending on its status code.
+var (
+	// ErrNotFound matches 404 responses.
+	ErrNotFound = errors.New("form3: resource not found")
+
+	// ErrConflict matches 409 responses, such as duplicated IDs or version conflicts.
+	ErrConflict = errors.New("form3: conflict")
+
+	// ErrRateLimited matches 429 responses.
+	ErrRateLimited = errors.New("form3: rate limited")
+
+	// ErrUnauthorized matches 401 and 403 responses.
+	ErrUnauthorized = errors.New("form3: unauthorized")
+
+	// ErrValidation matches 400 and 422 responses.
+	ErrValidation = errors.New("form3: validation failed")
+
+	// ErrServer matches 5xx responses.
+	ErrServer = errors.New("form3: server error")
+)
+
+// Is reports whether the error matches the target sentinel error, so callers can use errors.Is
+// instead of comparing status codes.
+func (e *Form3APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	case ErrServer:
+		return e.StatusCode >= 500 && e.StatusCode <= 599
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestForm3APIError_Is(t *testing.T) {
+	sentinels := []error{ErrNotFound, ErrConflict, ErrRateLimited, ErrUnauthorized, ErrValidation, ErrServer}
+
+	tests := []struct {
+		statusCode int
+		want       error
+	}{
+		{statusCode: 400, want: ErrValidation},
+		{statusCode: 401, want: ErrUnauthorized},
+		{statusCode: 403, want: ErrUnauthorized},
+		{statusCode: 404, want: ErrNotFound},
+		{statusCode: 409, want: ErrConflict},
+		{statusCode: 418, want: nil},
+		{statusCode: 422, want: ErrValidation},
+		{statusCode: 429, want: ErrRateLimited},
+		{statusCode: 500, want: ErrServer},
+		{statusCode: 503, want: ErrServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("Test status code %d", tt.statusCode), func(t *testing.T) {
+			// The error is wrapped like service methods do.
+			err := fmt.Errorf("error fetching account: %w", &Form3APIError{StatusCode: tt.statusCode})
+
+			for _, sentinel := range sentinels {
+				if got, want := errors.Is(err, sentinel), sentinel == tt.want; got != want {
+					t.Fatalf("errors.Is(%v) - got = %v, want %v", sentinel, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_Do_Form3APIErrorDetails(t *testing.T) {
+	body := `{"error_message": "Account cannot be created as it violates a duplicate constraint", "error_code": "7c8b0f6e-9a7d-4a53-8d4c-2c9d0e1c1a11"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	_, _, err = client.Account.Create(context.Background(), "10", "", &CreateAccountAttributes{BankID: "400300", BankIDCode: "GBDSC", Bic: "NWBKGB22", Country: "GB"})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("AccountService.Create() error - got = %v, want %v", err, ErrConflict)
+	}
+
+	var apiErr *Form3APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("AccountService.Create() - error type - got = %T, want %T", err, apiErr)
+	}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "Message", got: apiErr.Message, want: "Account cannot be created as it violates a duplicate constraint"},
+		{name: "ErrorCode", got: apiErr.ErrorCode, want: "7c8b0f6e-9a7d-4a53-8d4c-2c9d0e1c1a11"},
+		{name: "Body", got: string(apiErr.Body), want: body},
+		{name: "Header", got: apiErr.Header.Get("X-Request-Id"), want: "req-123"},
+		{name: "RequestID", got: apiErr.RequestID, want: "req-123"},
+		{name: "Operation", got: apiErr.Operation, want: OperationCreateAccount},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Fatalf("Form3APIError - %s - got = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
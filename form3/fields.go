@@ -0,0 +1,136 @@
+package form3
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ErrUnknownFields is matched by errors.Is on every UnknownFieldsError.
+var ErrUnknownFields = errors.New("unknown fields in response body")
+
+// UnknownFieldsError is returned with strict decoding when a response body has fields the client does not know about.
+type UnknownFieldsError struct {
+	// Paths are the paths of the unknown fields, such as "data.attributes.new_field". Array indexes are
+	// replaced with [], so the same field in every element is reported once.
+	Paths []string
+}
+
+func (e *UnknownFieldsError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrUnknownFields, strings.Join(e.Paths, ", "))
+}
+
+// Is reports whether the target is ErrUnknownFields.
+func (e *UnknownFieldsError) Is(target error) bool {
+	return target == ErrUnknownFields
+}
+
+// checksUnknownFields reports whether response bodies have to be checked for unknown fields.
+func (c *Client) checksUnknownFields() bool {
+	return c.StrictDecoding || c.UnknownFieldsHandler != nil
+}
+
+// reportUnknownFields handles the unknown fields found in the response body of the given request. It returns an error
+// with strict decoding, and it only reports them otherwise.
+func (c *Client) reportUnknownFields(r *Request, err *UnknownFieldsError) error {
+	if c.UnknownFieldsHandler != nil {
+		c.UnknownFieldsHandler(r.Operation, err.Paths)
+	}
+
+	if c.StrictDecoding {
+		return err
+	}
+	return nil
+}
+
+// findUnknownFields returns the paths of the fields of the JSON body which do not exist in the given result,
+// or nil if there are none.
+func findUnknownFields(body []byte, result interface{}) *UnknownFieldsError {
+	var value interface{}
+	if result == nil || json.Unmarshal(body, &value) != nil {
+		return nil
+	}
+
+	paths := map[string]bool{}
+	walkUnknownFields(value, reflect.TypeOf(result), "", paths)
+	if len(paths) == 0 {
+		return nil
+	}
+
+	err := &UnknownFieldsError{}
+	for path := range paths {
+		err.Paths = append(err.Paths, path)
+	}
+	sort.Strings(err.Paths)
+	return err
+}
+
+// walkUnknownFields adds to paths the fields of the decoded JSON value which have no matching field in the given type.
+func walkUnknownFields(value interface{}, t reflect.Type, path string, paths map[string]bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if t.Kind() != reflect.Struct {
+			// Maps and interfaces accept any field.
+			return
+		}
+
+		for key, fieldValue := range v {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+
+			field, ok := jsonField(t, key)
+			if !ok {
+				paths[fieldPath] = true
+				continue
+			}
+			walkUnknownFields(fieldValue, field.Type, fieldPath, paths)
+		}
+	case []interface{}:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return
+		}
+
+		for _, element := range v {
+			walkUnknownFields(element, t.Elem(), path+"[]", paths)
+		}
+	}
+}
+
+// jsonField returns the field of the struct type which encoding/json decodes the given key into.
+// As encoding/json does, an exact match of the name is preferred to a case-insensitive one.
+func jsonField(t reflect.Type, key string) (reflect.StructField, bool) {
+	var match reflect.StructField
+	found := false
+
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous && field.Type.Kind() == reflect.Struct {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		if name == key {
+			return field, true
+		}
+		if !found && strings.EqualFold(name, key) {
+			match, found = field, true
+		}
+	}
+
+	return match, found
+}
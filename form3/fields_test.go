@@ -0,0 +1,122 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func Test_findUnknownFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantPaths []string
+	}{
+		{
+			name: "Test known fields",
+			body: `{"data": {"id": "10", "version": 0, "attributes": {"country": "GB", "name": ["Samantha Holder"]}}, "links": {"self": "/v1/organisation/accounts/10"}}`,
+		},
+		{
+			name: "Test field names are matched case-insensitively",
+			body: `{"data": {"ID": "10"}}`,
+		},
+		{
+			name:      "Test nested unknown fields",
+			body:      `{"meta": {"count": 1}, "data": {"id": "10", "attributes": {"country": "GB", "iban_hidden": true, "new_field": "value"}}}`,
+			wantPaths: []string{"data.attributes.iban_hidden", "data.attributes.new_field", "meta"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths []string
+			if err := findUnknownFields([]byte(tt.body), &FetchAccountResponse{}); err != nil {
+				paths = err.Paths
+			}
+
+			if !reflect.DeepEqual(paths, tt.wantPaths) {
+				t.Fatalf("findUnknownFields() - paths - got = %v, want %v", paths, tt.wantPaths)
+			}
+		})
+	}
+}
+
+func Test_findUnknownFields_Array(t *testing.T) {
+	body := `{"data": [{"id": "1", "extra": 1}, {"id": "2", "extra": 2}]}`
+
+	err := findUnknownFields([]byte(body), &Form3BodyResponse[[]Account]{})
+	if err == nil || !reflect.DeepEqual(err.Paths, []string{"data[].extra"}) {
+		t.Fatalf("findUnknownFields() - got = %v, want %v", err, []string{"data[].extra"})
+	}
+}
+
+func TestClient_Do_UnknownFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write([]byte(`{"data": {"id": "10", "attributes": {"country": "GB", "new_field": "value"}}}`))
+	}))
+	defer server.Close()
+
+	registry := prometheus.NewRegistry()
+	metrics, err := NewPrometheusMetrics(registry)
+	if err != nil {
+		t.Fatalf("NewPrometheusMetrics() error = %v", err)
+	}
+
+	var reported []string
+	tests := []struct {
+		name         string
+		opts         []Option
+		wantErr      error
+		wantReported []string
+	}{
+		{
+			name: "Test unknown fields are ignored by default",
+		},
+		{
+			name: "Test unknown fields are reported",
+			opts: []Option{WithUnknownFieldsHandler(func(operation string, paths []string) {
+				reported = append(reported, operation)
+				reported = append(reported, paths...)
+				metrics.ObserveUnknownFields(operation, paths)
+			})},
+			wantReported: []string{OperationFetchAccount, "data.attributes.new_field"},
+		},
+		{
+			name:    "Test unknown fields fail with strict decoding",
+			opts:    []Option{WithStrictDecoding()},
+			wantErr: ErrUnknownFields,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reported = nil
+			client, err := NewClient(append([]Option{WithBaseURL(server.URL), WithHTTPClient(server.Client())}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			account, _, err := client.Account.Fetch(context.Background(), "10")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AccountService.Fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && account.ID != "10" {
+				t.Fatalf("AccountService.Fetch() - ID - got = %v, want %v", account.ID, "10")
+			}
+			if !reflect.DeepEqual(reported, tt.wantReported) {
+				t.Fatalf("UnknownFieldsHandler - got = %v, want %v", reported, tt.wantReported)
+			}
+		})
+	}
+
+	if got := testutil.ToFloat64(metrics.unknownFields.WithLabelValues(OperationFetchAccount, "data.attributes.new_field")); got != 1 {
+		t.Fatalf("unknown_fields_total - got = %v, want %v", got, 1)
+	}
+}
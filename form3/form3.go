@@ -1,21 +1,43 @@
 package form3
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"mime"
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
 	// defaultBaseURL is the default base URL for the Form3 API.
-	defaultBaseURL = "https://api.form3.tech/v1"
+	defaultBaseURL = "https://api.form3.tech/v1/"
+
+	// defaultUserAgent is the default User-Agent header sent with every request.
+	defaultUserAgent = "form3-client-go"
+
+	// maxBodySnippetSize is the maximum number of bytes of an unexpected response body kept in errors.
+	maxBodySnippetSize = 512
 )
 
+// ErrUnexpectedContentType is returned when a successful response body is not JSON.
+var ErrUnexpectedContentType = errors.New("unexpected response content type")
+
+// ErrSignerWithAuthenticator is returned when a client has both a Signer and an Authenticator, as both set the
+// Authorization header and the credentials would be lost.
+var ErrSignerWithAuthenticator = errors.New("a signer and an authenticator cannot be used together")
+
 type Form3BodyRequest[T any] struct {
 	Data T `json:"data"`
 }
@@ -33,12 +55,54 @@ type Form3BodyResponse[T any] struct {
 }
 
 type Form3BodyResponseError struct {
-	ErrorMessage string `json:"error_message"`
+	ErrorMessage string             `json:"error_message"`
+	ErrorCode    string             `json:"error_code,omitempty"`
+	Errors       []Form3ErrorDetail `json:"errors,omitempty"`
+}
+
+// Form3ErrorDetail describes a single problem of a failed request, such as an invalid attribute.
+type Form3ErrorDetail struct {
+	Code   string            `json:"code,omitempty"`
+	Title  string            `json:"title,omitempty"`
+	Detail string            `json:"detail,omitempty"`
+	Source *Form3ErrorSource `json:"source,omitempty"`
+}
+
+// Form3ErrorSource points to the part of the request that caused an error.
+type Form3ErrorSource struct {
+	// Pointer is a JSON pointer to the invalid attribute, for example "/data/attributes/bic".
+	Pointer string `json:"pointer,omitempty"`
+
+	// Parameter is the invalid query parameter.
+	Parameter string `json:"parameter,omitempty"`
 }
 
+// Form3APIError is returned when the Form3 API answers with an error status code.
+// Use errors.Is with the sentinel errors, such as ErrNotFound, to check the kind of error.
 type Form3APIError struct {
 	StatusCode int
 	Message    string
+
+	// ErrorCode is the Form3 error code, if any.
+	ErrorCode string
+
+	// Details lists the problems reported by the Form3 API, such as validation failures.
+	Details []Form3ErrorDetail
+
+	// Body is the raw response body. Bodies which are not JSON are truncated.
+	Body []byte
+
+	// ContentType is the media type of the response body.
+	ContentType string
+
+	// Header contains the response headers.
+	Header http.Header
+
+	// RequestID is the ID Form3 assigned to the request.
+	RequestID string
+
+	// Operation is the logical name of the failed operation, for example "accounts.fetch".
+	Operation string
 }
 
 func (e *Form3APIError) Error() string {
@@ -53,58 +117,256 @@ type Client struct {
 	// Base URL of the Form3 API.
 	BaseURL *url.URL
 
+	// RetryPolicy configures automatic retries of failed requests. Requests are not retried if nil.
+	RetryPolicy *RetryPolicy
+
+	// VersionRetry configures how many times operations needing the latest version of a resource, such as
+	// AccountService.DeleteLatest, fetch it again after a version conflict. They are not retried if nil.
+	VersionRetry *VersionRetryPolicy
+
+	// Cache stores the fetched resources for CacheTTL. Resources are always fetched from the Form3 API if nil.
+	Cache Cache
+
+	// CacheTTL is how long the fetched resources stay fresh in the Cache.
+	CacheTTL time.Duration
+
+	// RateLimiter throttles every request sent by the client, including retries. Requests are not throttled if nil.
+	RateLimiter *RateLimiter
+
+	// UserAgent is the User-Agent header sent with every request.
+	UserAgent string
+
+	// Header contains the default headers sent with every request.
+	Header http.Header
+
+	// OrganisationID is the organisation ID used when none is given to a service method.
+	OrganisationID string
+
+	// Hedging sends a second identical GET request when the first one is slow, and keeps the first successful
+	// response. Requests are not hedged if nil.
+	Hedging *HedgePolicy
+
+	// CircuitBreaker stops sending requests for a while after consecutive failures. Requests are always sent if nil.
+	CircuitBreaker *CircuitBreaker
+
+	// Authenticator adds credentials to every request. Requests rejected with a 401 status code are sent
+	// once more with new credentials. Requests are not authenticated if nil.
+	Authenticator Authenticator
+
+	// Signer signs every request before sending it. Requests are not signed if nil.
+	Signer Signer
+
+	// Logger is used to log every request attempt, with personal data and credentials redacted.
+	// Nothing is logged if nil.
+	Logger *slog.Logger
+
+	// LogLevels are the levels used to log requests depending on their outcome.
+	LogLevels LogLevels
+
+	// TracerProvider creates the spans of every request. The global provider is used if nil.
+	TracerProvider trace.TracerProvider
+
+	// Metrics records metrics about every request. Nothing is recorded if nil.
+	Metrics MetricsRecorder
+
+	// Propagator injects the trace context in the request headers. The global propagator is used if nil.
+	Propagator propagation.TextMapPropagator
+
+	// LegacyTimeoutErrors makes client side timeouts return a Form3APIError with a 504 status code, as
+	// previous versions did, instead of a TimeoutError.
+	LegacyTimeoutErrors bool
+
+	// middlewares are the middlewares registered with Use, the outermost first.
+	middlewares []Middleware
+
+	// StrictDecoding makes response bodies with fields unknown to the client fail with an UnknownFieldsError.
+	StrictDecoding bool
+
+	// UnknownFieldsHandler is called with the operation and the paths of the fields unknown to the client
+	// found in a response body, so API changes can be noticed. Bodies are not checked if nil, unless
+	// StrictDecoding is set.
+	UnknownFieldsHandler func(operation string, paths []string)
+
+	// MaxResponseSize is the maximum size in bytes of a response body. Larger bodies return ErrResponseTooLarge.
+	// It defaults to 10 MiB if zero.
+	MaxResponseSize int64
+
+	// cacheGenerations keeps fetches from storing resources invalidated while they were in flight.
+	cacheGenerations cacheGenerations
+
+	// timeout is the timeout set with WithTimeout, applied to a copy of the HTTP client.
+	timeout time.Duration
+
 	// Form3 services.
 	Account *AccountService
 }
 
-// NewClient returns a new Form3 API client.
-func NewClient(httpClient *http.Client) *Client {
-	if httpClient == nil {
-		httpClient = http.DefaultClient
+// NewClient returns a new Form3 API client configured with the given options.
+// All the options are validated and every invalid one is reported in the returned error.
+func NewClient(opts ...Option) (*Client, error) {
+	baseURL, _ := url.Parse(defaultBaseURL)
+
+	client := &Client{
+		client:    http.DefaultClient,
+		BaseURL:   baseURL,
+		UserAgent: defaultUserAgent,
+		Header:    http.Header{},
+		LogLevels: DefaultLogLevels(),
 	}
 
-	baseURL, _ := url.Parse(defaultBaseURL)
+	var errs []error
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(client); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if client.Signer != nil && client.Authenticator != nil {
+		errs = append(errs, ErrSignerWithAuthenticator)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid client configuration: %w", errors.Join(errs...))
+	}
 
-	client := &Client{client: httpClient, BaseURL: baseURL}
+	if client.timeout > 0 {
+		httpClient := *client.client
+		httpClient.Timeout = client.timeout
+		client.client = &httpClient
+	}
 
 	// attach services
 	client.Account = &AccountService{client: client}
 
-	return client
+	return client, nil
 }
 
 // Do sends HTTP API requests and returns the corresponding response or error.
+// Requests go through the registered middlewares and failing with a transient error are retried according
+// to the client RetryPolicy.
 func (c *Client) Do(ctx context.Context, method, url string, body, result interface{}) error {
-	req, err := c.newRequest(ctx, method, url, body)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
+	_, err := c.DoWithResponse(ctx, method, url, body, result)
+	return err
+}
 
-	res, err := c.client.Do(req)
-	if err != nil {
-		// Check if error is of type timeout.
-		if os.IsTimeout(err) {
-			return &Form3APIError{
-				StatusCode: http.StatusGatewayTimeout,
-				Message:    http.StatusText(http.StatusGatewayTimeout),
+// DoWithResponse works like Do but also returns the response metadata, such as the status code, the request ID
+// and the rate limit information. The response is returned whenever the Form3 API answered, even with an error.
+func (c *Client) DoWithResponse(ctx context.Context, method, url string, body, result interface{}) (*Response, error) {
+	return c.do(ctx, &Request{Method: method, URL: url, Body: body, Result: result})
+}
+
+// send sends the logical request to the Form3 API, retrying it if needed, and decodes the response.
+func (c *Client) send(ctx context.Context, r *Request) (*Response, error) {
+	reauthenticated := false
+
+	for attempt := 1; ; attempt++ {
+		circuit, err := c.allowCircuit(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send request: %w", err)
+		}
+
+		if err := c.waitRateLimiter(ctx, r); err != nil {
+			c.releaseCircuit(circuit)
+			return nil, fmt.Errorf("failed to wait for rate limiter: %w", err)
+		}
+
+		// The request is rebuilt on every attempt because its body can only be read once. It is built once the
+		// rate limiter let it through, so its Date, signature and credentials are fresh when sent.
+		req, err := c.newRequest(ctx, r.Method, r.URL, r.Body, r.Header)
+		if err != nil {
+			c.releaseCircuit(circuit)
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		res, err := c.hedgedAttempt(ctx, r, req, attempt)
+		c.doneCircuit(ctx, circuit, res, err)
+
+		// Credentials might have been revoked or expired early, so we get new ones and try once more.
+		if err == nil && res.StatusCode == http.StatusUnauthorized && c.Authenticator != nil && !reauthenticated {
+			drainBody(res)
+			c.Authenticator.Invalidate(req)
+			reauthenticated = true
+			continue
+		}
+
+		if retry, delay := c.shouldRetry(ctx, req, res, err, attempt); retry {
+			if res != nil {
+				drainBody(res)
 			}
+			c.metrics().IncRetry(metricsOperation(r), r.Method)
+			c.logRetry(ctx, r, attempt, delay)
+			if err := sleepContext(ctx, delay); err != nil {
+				return nil, fmt.Errorf("failed to send request: %w", err)
+			}
+			continue
 		}
-		return fmt.Errorf("failed to send request: %w", err)
-	}
-	defer res.Body.Close()
 
-	err = c.decodeBody(res, result)
+		if err != nil {
+			// Previous versions reported timeouts as if the server had answered with a 504 status code.
+			if c.LegacyTimeoutErrors && os.IsTimeout(err) {
+				return nil, &Form3APIError{
+					StatusCode: http.StatusGatewayTimeout,
+					Message:    http.StatusText(http.StatusGatewayTimeout),
+				}
+			}
+			return nil, fmt.Errorf("failed to send request: %w", contextError(ctx, err))
+		}
+		// The rest of the body is discarded so the connection can be reused.
+		defer drainBody(res)
+
+		response := newResponse(res, r.Result)
+
+		// A 304 answers a conditional request: what the caller has is still fresh, so there is nothing to decode.
+		if res.StatusCode == http.StatusNotModified && isConditional(r.Header) {
+			return response, nil
+		}
+
+		err = c.decodeBody(res, r.Result)
+
+		var unknownErr *UnknownFieldsError
+		if errors.As(err, &unknownErr) {
+			err = c.reportUnknownFields(r, unknownErr)
+		}
+
+		apiErr, ok := err.(*Form3APIError)
+		if err != nil && !ok {
+			return response, fmt.Errorf("failed to decode response body: %w", contextError(ctx, err))
+		}
+		if ok {
+			apiErr.Operation = r.Operation
+			return response, apiErr
+		}
 
-	_, ok := err.(*Form3APIError)
-	if err != nil && !ok {
-		return fmt.Errorf("failed to decode response body: %w", err)
+		return response, nil
 	}
+}
 
-	return err
+// attempt sends a single HTTP request, recording its metrics, span and logs.
+func (c *Client) attempt(ctx context.Context, r *Request, req *http.Request, attempt int) (*http.Response, error) {
+	operation := metricsOperation(r)
+	metrics := c.metrics()
+
+	span := c.startAttemptSpan(ctx, r, req, attempt)
+	metrics.AddInFlight(operation, r.Method, 1)
+	start := time.Now()
+
+	tracker, req := newPhaseTracker(req)
+	res, err := c.client.Do(req)
+	err = tracker.timeoutError(ctx, err)
+
+	duration := time.Since(start)
+	metrics.AddInFlight(operation, r.Method, -1)
+	metrics.ObserveRequest(operation, r.Method, statusClass(res), duration)
+	endAttemptSpan(span, r, res, err)
+	c.logAttempt(ctx, r, req, res, err, attempt, duration)
+	c.RateLimiter.Update(res)
+
+	return res, err
 }
 
-// newRequest creates an HTTP request with the given method, URL and body (if any).
-func (c *Client) newRequest(ctx context.Context, method, uri string, body interface{}) (*http.Request, error) {
+// newRequest creates an HTTP request with the given method, URL, body (if any) and request specific headers (if any).
+func (c *Client) newRequest(ctx context.Context, method, uri string, body interface{}, header http.Header) (*http.Request, error) {
 	// First we parse the uri which includes the path and query parameters.
 	parsedUri, err := url.Parse(uri)
 	if err != nil {
@@ -136,49 +398,185 @@ func (c *Client) newRequest(ctx context.Context, method, uri string, body interf
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	// Default headers go first so they cannot override the ones needed by the request.
+	for key, values := range c.Header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	for key, values := range header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	if key := idempotencyKey(ctx); key != "" {
+		req.Header.Set(idempotencyKeyHeader, key)
+	}
+
 	// We need to set the content-type to application/json if the body is not nil.
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
+	// Clients not built with NewClient are checked here.
+	if c.Authenticator != nil && c.Signer != nil {
+		return nil, ErrSignerWithAuthenticator
+	}
+
+	if c.Authenticator != nil {
+		if err := c.Authenticator.Authenticate(req); err != nil {
+			return nil, fmt.Errorf("failed to authenticate request: %w", err)
+		}
+	}
+
+	// The signature must be computed last, once all the signed headers are set.
+	if c.Signer != nil {
+		if err := c.Signer.Sign(req, marshalledBody); err != nil {
+			return nil, fmt.Errorf("failed to sign request: %w", err)
+		}
+	}
+
 	return req, nil
 }
 
 // decodeBody decodes the response body into the given result taking into account the status code and possible errors.
+// The body is decoded as it is read and it must not be larger than the client MaxResponseSize.
 func (c *Client) decodeBody(res *http.Response, result interface{}) error {
 	if res.StatusCode == http.StatusNoContent {
 		return nil
 	}
 
-	resBody, err := io.ReadAll(res.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
-	}
+	limit := c.maxResponseSize()
+	body := bufio.NewReader(newLimitedReader(res.Body, limit))
+	contentType := res.Header.Get("Content-Type")
 
 	// If the status code is not 2xx, we try to decode the response body as an error.
+	// Error bodies are small, so they are read at once and kept in the error.
 	if res.StatusCode < 200 || res.StatusCode > 299 {
-		if len(resBody) == 0 {
-			// If the response body is empty, we return a generic error.
-			return &Form3APIError{
-				StatusCode: res.StatusCode,
-				Message:    http.StatusText(res.StatusCode),
-			}
+		resBody, err := io.ReadAll(body)
+		if err != nil && !errors.Is(err, ErrResponseTooLarge) {
+			return fmt.Errorf("failed to read response body: %w", bodyReadError(err))
 		}
+		return newAPIError(res, contentType, resBody)
+	}
 
-		var errorResult Form3BodyResponseError
-		err = json.Unmarshal(resBody, &errorResult)
-		if err != nil {
-			return fmt.Errorf("failed to decode error response body: %w", err)
+	// There is no point in reading bodies larger than the limit.
+	if res.ContentLength > limit {
+		return tooLargeError(limit)
+	}
+
+	// The beginning of the body tells whether it is JSON when the Content-Type is missing or not reliable.
+	prefix, err := body.Peek(maxBodySnippetSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return fmt.Errorf("failed to read response body: %w", bodyReadError(err))
+	}
+
+	// Empty bodies are allowed, as long as there is nothing to decode them into.
+	if len(prefix) == 0 && result == nil {
+		return nil
+	}
+
+	if len(prefix) > 0 && !isJSONBody(contentType, prefix) {
+		return fmt.Errorf("%w %q with status code %d: %s", ErrUnexpectedContentType, contentType, res.StatusCode, truncateBody(prefix))
+	}
+
+	// The body is kept while decoding it only when it has to be checked for unknown fields.
+	var reader io.Reader = body
+	var raw bytes.Buffer
+	if c.checksUnknownFields() {
+		reader = io.TeeReader(body, &raw)
+	}
+
+	if err := json.NewDecoder(reader).Decode(result); err != nil {
+		return bodyReadError(err)
+	}
+
+	if c.checksUnknownFields() {
+		if unknownErr := findUnknownFields(raw.Bytes(), result); unknownErr != nil {
+			return unknownErr
 		}
+	}
+
+	return nil
+}
+
+// bodyReadError wraps timeouts while reading the response body in a TimeoutError.
+func bodyReadError(err error) error {
+	if os.IsTimeout(err) {
+		return &TimeoutError{Phase: TimeoutPhaseBody, Err: err}
+	}
+	return err
+}
 
-		// If the response body is not empty, we return the error message that we received in the response.
-		return &Form3APIError{
-			StatusCode: res.StatusCode,
-			Message:    errorResult.ErrorMessage,
+// newAPIError builds the error returned for a response with an error status code. The status code is kept even when
+// the body is not a Form3 error, for example the HTML page of a proxy.
+func newAPIError(res *http.Response, contentType string, body []byte) *Form3APIError {
+	apiErr := &Form3APIError{
+		StatusCode:  res.StatusCode,
+		Message:     http.StatusText(res.StatusCode),
+		Body:        body,
+		ContentType: contentType,
+		Header:      res.Header,
+		RequestID:   res.Header.Get(requestIDHeader),
+	}
+
+	// If the response body is empty, we return a generic error.
+	if len(body) == 0 {
+		return apiErr
+	}
+
+	var errorResult Form3BodyResponseError
+	if !isJSONBody(contentType, body) || json.Unmarshal(body, &errorResult) != nil {
+		apiErr.Body = []byte(truncateBody(body))
+		return apiErr
+	}
+
+	// If the response body is not empty, we return the error message that we received in the response.
+	apiErr.ErrorCode = errorResult.ErrorCode
+	apiErr.Details = errorResult.Errors
+	switch {
+	case errorResult.ErrorMessage != "":
+		apiErr.Message = errorResult.ErrorMessage
+	case len(errorResult.Errors) > 0:
+		messages := make([]string, 0, len(errorResult.Errors))
+		for _, detail := range errorResult.Errors {
+			if detail.Detail != "" {
+				messages = append(messages, detail.Detail)
+			} else if detail.Title != "" {
+				messages = append(messages, detail.Title)
+			}
 		}
+		if len(messages) > 0 {
+			apiErr.Message = strings.Join(messages, "; ")
+		}
+	}
+
+	return apiErr
+}
+
+// isJSONBody reports whether the response body is JSON according to its Content-Type header, such as
+// application/json or application/vnd.api+json. Bodies without a Content-Type or sent as plain text, which
+// happens with some proxies, are checked by looking at their first character.
+func isJSONBody(contentType string, body []byte) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")) {
+		return true
+	}
+
+	if contentType != "" && mediaType != "text/plain" {
+		return false
+	}
+
+	body = bytes.TrimSpace(body)
+	return len(body) > 0 && (body[0] == '{' || body[0] == '[')
+}
+
+// truncateBody returns the beginning of the given body, so it can be kept in errors.
+func truncateBody(body []byte) string {
+	if len(body) <= maxBodySnippetSize {
+		return string(body)
 	}
 
-	return json.Unmarshal(resBody, result)
+	return string(body[:maxBodySnippetSize]) + "..."
 }
 
 // Generic helper to convert a value to a pointer of the same type.
@@ -3,6 +3,7 @@ package form3
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -173,7 +174,7 @@ func TestClient_newRequest(t *testing.T) {
 			c := &Client{
 				BaseURL: tt.fields.BaseURL,
 			}
-			got, err := c.newRequest(context.Background(), tt.args.method, tt.args.uri, tt.args.body)
+			got, err := c.newRequest(context.Background(), tt.args.method, tt.args.uri, tt.args.body, nil)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("Client.newRequest() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -328,6 +329,94 @@ func TestClient_decodeBody(t *testing.T) {
 	}
 }
 
+func TestClient_decodeBody_ContentType(t *testing.T) {
+	htmlPage := "<html><body>" + strings.Repeat("Bad Gateway ", 100) + "</body></html>"
+
+	tests := []struct {
+		name        string
+		statusCode  int
+		contentType string
+		body        string
+		wantErr     error
+		wantAPIErr  *Form3APIError
+	}{
+		{
+			name:        "Test HTML error page from a proxy",
+			statusCode:  http.StatusBadGateway,
+			contentType: "text/html; charset=utf-8",
+			body:        htmlPage,
+			wantAPIErr:  &Form3APIError{StatusCode: http.StatusBadGateway, Message: http.StatusText(http.StatusBadGateway), Body: []byte(htmlPage[:maxBodySnippetSize] + "...")},
+		},
+		{
+			name:        "Test malformed JSON error",
+			statusCode:  http.StatusInternalServerError,
+			contentType: "application/json",
+			body:        `{"error_message": `,
+			wantAPIErr:  &Form3APIError{StatusCode: http.StatusInternalServerError, Message: http.StatusText(http.StatusInternalServerError), Body: []byte(`{"error_message": `)},
+		},
+		{
+			name:        "Test error with validation details",
+			statusCode:  http.StatusBadRequest,
+			contentType: "application/vnd.api+json",
+			body:        `{"error_code": "c1a9", "errors": [{"code": "invalid", "detail": "bic is invalid", "source": {"pointer": "/data/attributes/bic"}}, {"title": "country is required"}]}`,
+			wantAPIErr: &Form3APIError{
+				StatusCode: http.StatusBadRequest,
+				Message:    "bic is invalid; country is required",
+				ErrorCode:  "c1a9",
+				Details: []Form3ErrorDetail{
+					{Code: "invalid", Detail: "bic is invalid", Source: &Form3ErrorSource{Pointer: "/data/attributes/bic"}},
+					{Title: "country is required"},
+				},
+				Body: []byte(`{"error_code": "c1a9", "errors": [{"code": "invalid", "detail": "bic is invalid", "source": {"pointer": "/data/attributes/bic"}}, {"title": "country is required"}]}`),
+			},
+		},
+		{
+			name:        "Test success with unexpected media type",
+			statusCode:  http.StatusOK,
+			contentType: "text/html",
+			body:        "<html></html>",
+			wantErr:     ErrUnexpectedContentType,
+		},
+		{
+			name:        "Test success with JSON:API media type",
+			statusCode:  http.StatusOK,
+			contentType: "application/vnd.api+json",
+			body:        `{"data": {"id": "10"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &http.Response{
+				StatusCode: tt.statusCode,
+				Header:     http.Header{"Content-Type": []string{tt.contentType}},
+				Body:       io.NopCloser(strings.NewReader(tt.body)),
+			}
+
+			var result map[string]interface{}
+			err := (&Client{}).decodeBody(res, &result)
+
+			if tt.wantAPIErr == nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Client.decodeBody() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			apiErr, ok := err.(*Form3APIError)
+			if !ok {
+				t.Fatalf("Client.decodeBody() - error type - got = %T, want %T", err, apiErr)
+			}
+
+			apiErr.Header = nil
+			tt.wantAPIErr.ContentType = tt.contentType
+			if !reflect.DeepEqual(apiErr, tt.wantAPIErr) {
+				t.Fatalf("Client.decodeBody() - error - got = %+v, want %+v", apiErr, tt.wantAPIErr)
+			}
+		})
+	}
+}
+
 func TestClient_Do(t *testing.T) {
 	testCases := []struct {
 		name         string
@@ -336,6 +425,7 @@ func TestClient_Do(t *testing.T) {
 		body         interface{}
 		handleFunc   http.HandlerFunc
 		expectedBody string
+		legacy       bool
 		wantErr      *Form3APIError
 	}{
 		{
@@ -377,7 +467,7 @@ func TestClient_Do(t *testing.T) {
 			expectedBody: `{"data":{"id":"10000"}}`,
 		},
 		{
-			name:   "Test Client.Do() with timeout",
+			name:   "Test Client.Do() with timeout and legacy timeout errors",
 			method: http.MethodGet,
 			path:   "/v1/accounts/10",
 			handleFunc: func(w http.ResponseWriter, r *http.Request) {
@@ -385,6 +475,7 @@ func TestClient_Do(t *testing.T) {
 				w.WriteHeader(http.StatusOK)
 				w.Write([]byte(`{"data": {"id": "10"}}`))
 			},
+			legacy:  true,
 			wantErr: &Form3APIError{StatusCode: http.StatusGatewayTimeout, Message: http.StatusText(http.StatusGatewayTimeout)},
 		},
 	}
@@ -397,7 +488,7 @@ func TestClient_Do(t *testing.T) {
 
 			// Setup client
 			serverUrl, _ := url.Parse(server.URL)
-			client := &Client{BaseURL: serverUrl, client: &http.Client{Timeout: 1 * time.Second}}
+			client := &Client{BaseURL: serverUrl, client: &http.Client{Timeout: 1 * time.Second}, LegacyTimeoutErrors: tc.legacy}
 
 			// Make request
 			var result map[string]interface{}
@@ -0,0 +1,252 @@
+package form3
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Hedging defaults
+const (
+	defaultMaxHedgeRate = 0.1
+
+	// hedgeWindow is the number of recent requests used to learn the latency percentile and to cap the hedge rate.
+	hedgeWindow = 100
+
+	// minHedgeSamples is the number of latencies needed before the percentile is used instead of the delay.
+	minHedgeSamples = 20
+)
+
+// HedgePolicy configures hedged requests: when a GET request takes longer than usual, an identical request is sent,
+// the first successful response wins and the other request is cancelled. This cuts the tail latency at the cost of
+// a few more requests. It is safe for concurrent use.
+type HedgePolicy struct {
+	// Delay is how long to wait for a response before sending the hedged request. It is used until enough
+	// latencies are known when Percentile is set.
+	Delay time.Duration
+
+	// Percentile, between 0 and 1, makes the delay the given percentile of the recent latencies, for example 0.95.
+	// The fixed Delay is used if zero.
+	Percentile float64
+
+	// MaxHedgeRate is the maximum fraction of the last 100 requests which can be hedged, so hedging does not double
+	// the load during incidents, when every request is slow. It defaults to 10% if zero.
+	MaxHedgeRate float64
+
+	mu        sync.Mutex
+	latencies []time.Duration
+	hedged    []bool
+	next      int
+
+	// reserved is the number of hedges allowed for requests which are not observed yet.
+	reserved int
+}
+
+// NewHedgePolicy returns a hedge policy sending the hedged request after the given delay, for up to 10% of
+// the requests.
+func NewHedgePolicy(delay time.Duration) *HedgePolicy {
+	return &HedgePolicy{Delay: delay, MaxHedgeRate: defaultMaxHedgeRate}
+}
+
+// delay returns how long to wait before sending the hedged request.
+func (p *HedgePolicy) delay() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.Percentile <= 0 || len(p.latencies) < minHedgeSamples {
+		return p.Delay
+	}
+
+	sorted := append([]time.Duration(nil), p.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	index := int(p.Percentile * float64(len(sorted)-1))
+	return sorted[min(index, len(sorted)-1)]
+}
+
+// observe records the latency of a request and whether it was hedged. The hedge reserved for the request, if any,
+// is recorded in its place.
+func (p *HedgePolicy) observe(latency time.Duration, hedged bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if hedged {
+		p.reserved--
+	}
+
+	if len(p.latencies) < hedgeWindow {
+		p.latencies = append(p.latencies, latency)
+		p.hedged = append(p.hedged, hedged)
+		return
+	}
+
+	p.latencies[p.next] = latency
+	p.hedged[p.next] = hedged
+	p.next = (p.next + 1) % hedgeWindow
+}
+
+// reserveHedge reports whether the hedge rate of the recent requests allows hedging one more, and reserves the
+// hedge if so. The hedges of the requests in flight are counted too, so the rate holds when every request is slow.
+// The reservation is released by observe, or by releaseHedge if the hedge is not sent.
+func (p *HedgePolicy) reserveHedge() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	hedges := p.reserved
+	for _, hedged := range p.hedged {
+		if hedged {
+			hedges++
+		}
+	}
+
+	rate := p.MaxHedgeRate
+	if rate <= 0 {
+		rate = defaultMaxHedgeRate
+	}
+
+	// The budget is a fraction of the whole window, so the first requests can be hedged before there is any
+	// history.
+	if float64(hedges+1) > rate*hedgeWindow {
+		return false
+	}
+	p.reserved++
+	return true
+}
+
+// releaseHedge releases a hedge reserved for a request which was not sent after all.
+func (p *HedgePolicy) releaseHedge() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.reserved--
+}
+
+// hedgeResult is the outcome of one of the requests of a hedged attempt.
+type hedgeResult struct {
+	index int
+	res   *http.Response
+	err   error
+
+	// unsent tells the request could not be built, so it was never sent.
+	unsent bool
+}
+
+// succeeded reports whether the request got a response which is not a server error.
+func (r hedgeResult) succeeded() bool {
+	return r.err == nil && r.res.StatusCode < http.StatusInternalServerError
+}
+
+// hedgedAttempt sends a single attempt like attempt does, but GET requests are hedged according to the client
+// Hedging policy.
+func (c *Client) hedgedAttempt(ctx context.Context, r *Request, req *http.Request, attempt int) (*http.Response, error) {
+	p := c.Hedging
+	if p == nil || r.Method != http.MethodGet {
+		return c.attempt(ctx, r, req, attempt)
+	}
+
+	start := time.Now()
+	results := make(chan hedgeResult, 2)
+	var cancels []context.CancelFunc
+
+	// send sends the request in the background, with a context cancelled when the request loses the race.
+	send := func(build func(ctx context.Context) (*http.Request, error)) {
+		reqCtx, cancel := context.WithCancel(ctx)
+		index := len(cancels)
+		cancels = append(cancels, cancel)
+
+		go func() {
+			req, err := build(reqCtx)
+			if err != nil {
+				results <- hedgeResult{index: index, err: err, unsent: true}
+				return
+			}
+			res, err := c.attempt(reqCtx, r, req, attempt)
+			results <- hedgeResult{index: index, res: res, err: err}
+		}()
+	}
+
+	send(func(ctx context.Context) (*http.Request, error) {
+		return req.WithContext(ctx), nil
+	})
+	pending := 1
+
+	timer := time.NewTimer(p.delay())
+	defer timer.Stop()
+
+	hedged := false
+	for {
+		select {
+		case <-timer.C:
+			if !p.reserveHedge() {
+				continue
+			}
+
+			// The hedged request is built again, so it gets its own credentials and signature.
+			hedged = true
+			pending++
+			send(func(ctx context.Context) (*http.Request, error) {
+				if err := c.waitRateLimiter(ctx, r); err != nil {
+					return nil, err
+				}
+				return c.newRequest(ctx, r.Method, r.URL, r.Body, r.Header)
+			})
+		case result := <-results:
+			pending--
+			if result.unsent {
+				p.releaseHedge()
+				hedged = false
+			}
+			if !result.succeeded() && pending > 0 {
+				// The other request might still succeed.
+				discardHedge(result, cancels[result.index])
+				continue
+			}
+
+			p.observe(time.Since(start), hedged)
+
+			// The losing request is cancelled straight away, and released once done.
+			for i, cancel := range cancels {
+				if i != result.index {
+					cancel()
+				}
+			}
+			if pending > 0 {
+				go func() {
+					loser := <-results
+					discardHedge(loser, cancels[loser.index])
+				}()
+			}
+
+			if result.res == nil {
+				cancels[result.index]()
+				return nil, result.err
+			}
+
+			// The winning request is cancelled once its body is closed.
+			result.res.Body = &cancelOnClose{ReadCloser: result.res.Body, cancel: cancels[result.index]}
+			return result.res, nil
+		}
+	}
+}
+
+// discardHedge cancels a request which lost the race and releases its response, if any.
+func discardHedge(result hedgeResult, cancel context.CancelFunc) {
+	cancel()
+	if result.res != nil {
+		drainBody(result.res)
+	}
+}
+
+// cancelOnClose cancels the context of a request when its response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
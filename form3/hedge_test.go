@@ -0,0 +1,148 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_Do_Hedging(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		maxHedgeRate float64
+		wantRequests int32
+		wantFast     bool
+	}{
+		{name: "Test slow GET is hedged", method: http.MethodGet, maxHedgeRate: 1, wantRequests: 2, wantFast: true},
+		{name: "Test first request is hedged with the default rate", method: http.MethodGet, maxHedgeRate: 0, wantRequests: 2, wantFast: true},
+		{name: "Test DELETE is not hedged", method: http.MethodDelete, maxHedgeRate: 1, wantRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			loserCancelled := make(chan struct{})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// The first request is slow, the hedged one is fast.
+				if atomic.AddInt32(&requests, 1) == 1 {
+					select {
+					case <-r.Context().Done():
+						close(loserCancelled)
+						return
+					case <-time.After(300 * time.Millisecond):
+					}
+				}
+				w.Write([]byte(`{"data": {"id": "10"}}`))
+			}))
+			defer server.Close()
+
+			policy := NewHedgePolicy(20 * time.Millisecond)
+			policy.MaxHedgeRate = tt.maxHedgeRate
+			client, err := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithHedging(policy))
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			start := time.Now()
+			var result FetchAccountResponse
+			if err := client.Do(context.Background(), tt.method, "organisation/accounts/10", nil, &result); err != nil {
+				t.Fatalf("Client.Do() error = %v", err)
+			}
+
+			if fast := time.Since(start) < 200*time.Millisecond; fast != tt.wantFast {
+				t.Fatalf("Client.Do() - fast - got = %v, want %v", fast, tt.wantFast)
+			}
+			if result.Data.ID != "10" {
+				t.Fatalf("Client.Do() - ID - got = %v, want %v", result.Data.ID, "10")
+			}
+			if got := atomic.LoadInt32(&requests); got != tt.wantRequests {
+				t.Fatalf("Client.Do() - requests - got = %v, want %v", got, tt.wantRequests)
+			}
+
+			if tt.wantFast {
+				select {
+				case <-loserCancelled:
+				case <-time.After(time.Second):
+					t.Fatalf("Client.Do() - the slow request was not cancelled")
+				}
+			}
+		})
+	}
+}
+
+func TestClient_Do_Hedging_Concurrent(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Every request is slow, as during an incident.
+		atomic.AddInt32(&requests, 1)
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte(`{"data": {"id": "10"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithHedging(NewHedgePolicy(10*time.Millisecond)))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	const calls = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, calls)
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- client.Do(context.Background(), http.MethodGet, "organisation/accounts/10", nil, &FetchAccountResponse{})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Client.Do() error = %v", err)
+		}
+	}
+
+	// The default rate allows hedging 10% of the window, even though every request is in flight at once.
+	want := int32(calls + defaultMaxHedgeRate*hedgeWindow)
+	if got := atomic.LoadInt32(&requests); got != want {
+		t.Fatalf("Client.Do() - requests - got = %v, want %v", got, want)
+	}
+	if policy := client.Hedging; policy.reserved != 0 {
+		t.Fatalf("HedgePolicy - reserved - got = %v, want 0", policy.reserved)
+	}
+}
+
+func TestHedgePolicy_delay(t *testing.T) {
+	tests := []struct {
+		name       string
+		percentile float64
+		samples    int
+		want       time.Duration
+	}{
+		{name: "Test fixed delay", samples: 50, want: time.Second},
+		{name: "Test fixed delay until enough latencies are known", percentile: 0.5, samples: minHedgeSamples - 1, want: time.Second},
+		{name: "Test learned percentile", percentile: 0.5, samples: 51, want: 25 * time.Millisecond},
+		{name: "Test only recent latencies are used", percentile: 0.5, samples: hedgeWindow + 50, want: 99 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := NewHedgePolicy(time.Second)
+			policy.Percentile = tt.percentile
+			for i := 0; i < tt.samples; i++ {
+				policy.observe(time.Duration(i)*time.Millisecond, false)
+			}
+
+			if got := policy.delay(); got != tt.want {
+				t.Fatalf("HedgePolicy.delay() - got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
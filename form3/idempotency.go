@@ -0,0 +1,190 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// idempotencyKeyHeader is the header used to send the idempotency key of a request.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// ErrIdempotencyMismatch is matched by errors.Is on every IdempotencyMismatchError.
+var ErrIdempotencyMismatch = errors.New("existing resource does not match the request")
+
+// IdempotencyMismatchError is returned by an idempotent create when a resource with the same ID already exists
+// but it differs from the requested one.
+type IdempotencyMismatchError struct {
+	// ID is the ID of the existing resource.
+	ID string
+
+	// Diffs lists the fields whose existing value differs from the requested one.
+	Diffs []FieldDiff
+}
+
+// FieldDiff is a field whose existing value differs from the requested one.
+type FieldDiff struct {
+	// Field is the JSON name of the field, such as "bank_id".
+	Field string
+
+	Requested interface{}
+	Existing  interface{}
+}
+
+func (e *IdempotencyMismatchError) Error() string {
+	diffs := make([]string, len(e.Diffs))
+	for i, diff := range e.Diffs {
+		diffs[i] = fmt.Sprintf("%s: requested %v, existing %v", diff.Field, diff.Requested, diff.Existing)
+	}
+	return fmt.Sprintf("%s %s: %s", ErrIdempotencyMismatch, e.ID, strings.Join(diffs, "; "))
+}
+
+// Is reports whether the target is ErrIdempotencyMismatch.
+func (e *IdempotencyMismatchError) Is(target error) bool {
+	return target == ErrIdempotencyMismatch
+}
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a copy of the context which makes the requests sent with it carry the given
+// idempotency key in the Idempotency-Key header, for the endpoints which accept one. Such requests are
+// retried as if they were idempotent.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// idempotencyKey returns the idempotency key set in the context with WithIdempotencyKey, if any.
+func idempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key
+}
+
+// CreateIdempotent creates a new account like Create, but it can be safely called again with the same arguments,
+// for example after a timeout. If an account with the same ID already exists, it is fetched and returned when it
+// matches the requested one. Otherwise an IdempotencyMismatchError listing the differences is returned.
+func (as *AccountService) CreateIdempotent(ctx context.Context, ID string, organisationID string, attributes *CreateAccountAttributes) (*Account, *Response, error) {
+	account, res, err := as.Create(ctx, ID, organisationID, attributes)
+	if !errors.Is(err, ErrConflict) {
+		return account, res, err
+	}
+
+	// The existing account is compared as it is now, not as cached.
+	existing, fetchRes, fetchErr := as.fetchAccount(ctx, ID, true)
+	if fetchErr != nil {
+		// The conflict might not be caused by the ID, so the original error is kept.
+		return nil, res, err
+	}
+
+	if organisationID == "" {
+		organisationID = as.client.OrganisationID
+	}
+
+	diffs := diffAccount(organisationID, attributes, existing)
+	if len(diffs) > 0 {
+		return nil, fetchRes, fmt.Errorf("error creating account: %w", &IdempotencyMismatchError{ID: ID, Diffs: diffs})
+	}
+
+	return existing, fetchRes, nil
+}
+
+// diffAccount compares the requested account with the existing one. Optional attributes which were not requested
+// are not compared, as they might have been set to their default value by the Form3 API.
+func diffAccount(organisationID string, requested *CreateAccountAttributes, existing *Account) []FieldDiff {
+	var diffs []FieldDiff
+	add := func(field string, requested, existing interface{}) {
+		if !reflect.DeepEqual(requested, existing) {
+			diffs = append(diffs, FieldDiff{Field: field, Requested: requested, Existing: existing})
+		}
+	}
+
+	if organisationID != "" {
+		add("organisation_id", organisationID, existing.OrganisationID)
+	}
+
+	if requested == nil {
+		return diffs
+	}
+
+	attributes := existing.Attributes
+	if attributes == nil {
+		attributes = &AccountAttributes{}
+	}
+
+	add("bank_id", requested.BankID, attributes.BankID)
+	add("bank_id_code", requested.BankIDCode, attributes.BankIDCode)
+	add("bic", requested.Bic, attributes.Bic)
+	add("country", requested.Country, valueOf(attributes.Country))
+	if len(requested.Name) > 0 {
+		add("name", requested.Name, attributes.Name)
+	}
+	if requested.AccountClassification != nil {
+		add("account_classification", *requested.AccountClassification, valueOf(attributes.AccountClassification))
+	}
+	if requested.AccountNumber != nil {
+		add("account_number", *requested.AccountNumber, attributes.AccountNumber)
+	}
+	if alternativeNames := valueOf(requested.AlternativeNames); len(alternativeNames) > 0 {
+		add("alternative_names", alternativeNames, attributes.AlternativeNames)
+	}
+	if requested.BaseCurrency != nil {
+		add("base_currency", *requested.BaseCurrency, attributes.BaseCurrency)
+	}
+	if requested.Iban != nil {
+		add("iban", *requested.Iban, attributes.Iban)
+	}
+	if requested.JointAccount != nil {
+		add("joint_account", *requested.JointAccount, valueOf(attributes.JointAccount))
+	}
+	if requested.SecondaryIdentification != nil {
+		add("secondary_identification", *requested.SecondaryIdentification, attributes.SecondaryIdentification)
+	}
+	if requested.AcceptanceQualifier != nil {
+		add("acceptance_qualifier", *requested.AcceptanceQualifier, valueOf(attributes.AcceptanceQualifier))
+	}
+	if requested.AccountMatchingOptOut != nil {
+		add("account_matching_opt_out", *requested.AccountMatchingOptOut, valueOf(attributes.AccountMatchingOptOut))
+	}
+	if requested.NameMatchingStatus != nil {
+		add("name_matching_status", *requested.NameMatchingStatus, valueOf(attributes.NameMatchingStatus))
+	}
+	if requested.ProcessingService != nil {
+		add("processing_service", *requested.ProcessingService, valueOf(attributes.ProcessingService))
+	}
+	if requested.ReferenceMask != nil {
+		add("reference_mask", *requested.ReferenceMask, valueOf(attributes.ReferenceMask))
+	}
+	if requested.Status != nil {
+		add("status", *requested.Status, valueOf(attributes.Status))
+	}
+	if requested.Switched != nil {
+		add("switched", *requested.Switched, valueOf(attributes.Switched))
+	}
+	if len(requested.UserDefinedData) > 0 {
+		add("user_defined_data", requested.UserDefinedData, attributes.UserDefinedData)
+	}
+	if requested.UserDefinedInformation != nil {
+		add("user_defined_information", *requested.UserDefinedInformation, valueOf(attributes.UserDefinedInformation))
+	}
+	if requested.ValidationType != nil {
+		add("validation_type", *requested.ValidationType, valueOf(attributes.ValidationType))
+	}
+	if requested.PrivateIdentification != nil {
+		add("private_identification", *requested.PrivateIdentification, valueOf(attributes.PrivateIdentification))
+	}
+	if requested.OrganisationIdentification != nil {
+		add("organisation_identification", *requested.OrganisationIdentification, valueOf(attributes.OrganisationIdentification))
+	}
+
+	return diffs
+}
+
+// valueOf returns the value the pointer points to, or the zero value if nil.
+func valueOf[T any](pointer *T) T {
+	var value T
+	if pointer != nil {
+		value = *pointer
+	}
+	return value
+}
@@ -0,0 +1,133 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAccountService_CreateIdempotent(t *testing.T) {
+	existing := `{"data": {"id": "10", "organisation_id": "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c", "version": 0, "attributes": {"bank_id": "400300", "bank_id_code": "GBDSC", "bic": "NWBKGB22", "country": "GB", "name": ["Samantha Holder"], "account_number": "41426819", "base_currency": "GBP", "validation_type": "card", "user_defined_data": [{"key": "k", "value": "v"}]}}}`
+
+	tests := []struct {
+		name       string
+		created    bool
+		attributes *CreateAccountAttributes
+		wantErr    error
+		wantDiffs  []FieldDiff
+	}{
+		{
+			name:       "Test account is created",
+			created:    true,
+			attributes: &CreateAccountAttributes{BankID: "400300", BankIDCode: "GBDSC", Bic: "NWBKGB22", Country: "GB"},
+		},
+		{
+			name:       "Test matching existing account is returned",
+			attributes: &CreateAccountAttributes{BankID: "400300", BankIDCode: "GBDSC", Bic: "NWBKGB22", Country: "GB", Name: []string{"Samantha Holder"}, AccountNumber: ToPointer("41426819")},
+		},
+		{
+			name:       "Test empty alternative names match an account without alternative names",
+			attributes: &CreateAccountAttributes{BankID: "400300", BankIDCode: "GBDSC", Bic: "NWBKGB22", Country: "GB", AlternativeNames: &[]string{}},
+		},
+		{
+			name:       "Test different existing account is reported",
+			attributes: &CreateAccountAttributes{BankID: "400301", BankIDCode: "GBDSC", Bic: "NWBKGB22", Country: "GB", BaseCurrency: ToPointer("EUR")},
+			wantErr:    ErrIdempotencyMismatch,
+			wantDiffs: []FieldDiff{
+				{Field: "bank_id", Requested: "400301", Existing: "400300"},
+				{Field: "base_currency", Requested: "EUR", Existing: "GBP"},
+			},
+		},
+		{
+			name: "Test different optional attributes are reported",
+			attributes: &CreateAccountAttributes{
+				BankID: "400300", BankIDCode: "GBDSC", Bic: "NWBKGB22", Country: "GB",
+				ValidationType:  ToPointer("card"),
+				UserDefinedData: []AccountUserDefinedData{{Key: "k", Value: "other"}},
+				ReferenceMask:   ToPointer("####"),
+			},
+			wantErr: ErrIdempotencyMismatch,
+			wantDiffs: []FieldDiff{
+				{Field: "reference_mask", Requested: "####", Existing: ""},
+				{Field: "user_defined_data", Requested: []AccountUserDefinedData{{Key: "k", Value: "other"}}, Existing: []AccountUserDefinedData{{Key: "k", Value: "v"}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPost && tt.created:
+					w.WriteHeader(http.StatusCreated)
+					w.Write([]byte(`{"data": {"id": "10"}}`))
+				case r.Method == http.MethodPost:
+					w.WriteHeader(http.StatusConflict)
+					w.Write([]byte(`{"error_message": "Account cannot be created as it violates a duplicate constraint"}`))
+				default:
+					w.Write([]byte(existing))
+				}
+			}))
+			defer server.Close()
+
+			client, err := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithOrganisationID("eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"))
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			account, _, err := client.Account.CreateIdempotent(context.Background(), "10", "", tt.attributes)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AccountService.CreateIdempotent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				var mismatchErr *IdempotencyMismatchError
+				if !errors.As(err, &mismatchErr) {
+					t.Fatalf("AccountService.CreateIdempotent() - error type - got = %T, want %T", err, mismatchErr)
+				}
+				if !reflect.DeepEqual(mismatchErr.Diffs, tt.wantDiffs) {
+					t.Fatalf("AccountService.CreateIdempotent() - Diffs - got = %+v, want %+v", mismatchErr.Diffs, tt.wantDiffs)
+				}
+				return
+			}
+
+			if account == nil || account.ID != "10" {
+				t.Fatalf("AccountService.CreateIdempotent() - account - got = %+v, want ID %v", account, "10")
+			}
+		})
+	}
+}
+
+func TestClient_Do_IdempotencyKey(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Idempotency-Key"); got != "key-1" {
+			t.Errorf("Idempotency-Key header - got = %v, want %v", got, "key-1")
+		}
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data": {"id": "10"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithRetryPolicy(testRetryPolicy(2)))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	// POST requests are only retried on server errors when they carry an idempotency key.
+	ctx := WithIdempotencyKey(context.Background(), "key-1")
+	if _, _, err := client.Account.Create(ctx, "10", "", &CreateAccountAttributes{BankID: "400300", BankIDCode: "GBDSC", Bic: "NWBKGB22", Country: "GB"}); err != nil {
+		t.Fatalf("AccountService.Create() error = %v", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("AccountService.Create() - attempts - got = %v, want %v", attempts, 2)
+	}
+}
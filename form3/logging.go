@@ -0,0 +1,273 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// redacted replaces sensitive values in logs.
+const redacted = "REDACTED"
+
+// requestIDHeader is the header the Form3 API uses to identify a request.
+const requestIDHeader = "X-Request-Id"
+
+// sensitiveHeaders are the request headers whose values are never logged.
+var sensitiveHeaders = []string{"Authorization", "Signature", "Proxy-Authorization", "Cookie"}
+
+// LogLevels are the levels used to log requests depending on their outcome.
+type LogLevels struct {
+	// Success is used for requests answered with a 2xx status code.
+	Success slog.Level
+
+	// ClientError is used for requests answered with a 4xx status code.
+	ClientError slog.Level
+
+	// ServerError is used for requests answered with a 5xx status code or failing without response.
+	ServerError slog.Level
+
+	// Retry is used when a failed request is going to be retried.
+	Retry slog.Level
+}
+
+// DefaultLogLevels returns the levels used when none are configured.
+func DefaultLogLevels() LogLevels {
+	return LogLevels{
+		Success:     slog.LevelDebug,
+		ClientError: slog.LevelWarn,
+		ServerError: slog.LevelError,
+		Retry:       slog.LevelInfo,
+	}
+}
+
+// logAttempt logs a single HTTP attempt of a logical request. Only the path is logged, as query parameters
+// might contain filters with personal data. Request headers are logged at debug level, redacted.
+func (c *Client) logAttempt(ctx context.Context, r *Request, req *http.Request, res *http.Response, err error, attempt int, duration time.Duration) {
+	if c.Logger == nil {
+		return
+	}
+
+	level := c.LogLevels.ServerError
+	attrs := []slog.Attr{
+		slog.String("operation", r.Operation),
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.Int("attempt", attempt),
+		slog.Duration("duration", duration),
+	}
+
+	if res != nil {
+		switch {
+		case res.StatusCode < 400:
+			level = c.LogLevels.Success
+		case res.StatusCode < 500:
+			level = c.LogLevels.ClientError
+		}
+		attrs = append(attrs, slog.Int("status", res.StatusCode), slog.String("request_id", res.Header.Get(requestIDHeader)))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", redactErrorURL(err, req.URL.Path)))
+	}
+
+	if !c.Logger.Enabled(ctx, level) {
+		return
+	}
+	if c.Logger.Enabled(ctx, slog.LevelDebug) {
+		attrs = append(attrs, slog.Any("request_headers", redactHeaders(req.Header)))
+	}
+
+	c.Logger.LogAttrs(ctx, level, "Form3 API request", attrs...)
+}
+
+// logRetry logs that a failed attempt of a logical request is going to be retried.
+func (c *Client) logRetry(ctx context.Context, r *Request, attempt int, delay time.Duration) {
+	if c.Logger == nil {
+		return
+	}
+
+	c.Logger.LogAttrs(ctx, c.LogLevels.Retry, "retrying Form3 API request",
+		slog.String("operation", r.Operation),
+		slog.String("method", r.Method),
+		slog.Int("attempt", attempt),
+		slog.Duration("delay", delay),
+	)
+}
+
+// redactErrorURL returns the message of the error with the URL quoted by transport errors, such as *url.Error,
+// replaced by the given one, as its query might contain filters with personal data.
+func redactErrorURL(err error, replacement string) string {
+	message := err.Error()
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) && urlErr.URL != "" {
+		message = strings.ReplaceAll(message, urlErr.URL, replacement)
+	}
+	return message
+}
+
+// redactHeaders returns a copy of the headers with the values of the sensitive ones redacted.
+func redactHeaders(header http.Header) http.Header {
+	redactedHeader := header.Clone()
+	for _, key := range sensitiveHeaders {
+		if redactedHeader.Get(key) != "" {
+			redactedHeader.Set(key, redacted)
+		}
+	}
+	return redactedHeader
+}
+
+// mask hides all but the last 4 characters of a sensitive identifier, so it can still be told apart in logs.
+func mask(value string) string {
+	if len(value) <= 4 {
+		return strings.Repeat("*", len(value))
+	}
+	return strings.Repeat("*", len(value)-4) + value[len(value)-4:]
+}
+
+// maskNames redacts a list of names.
+func maskNames(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	return redacted
+}
+
+// LogValue implements slog.LogValuer, so accounts never leak personal data into logs.
+func (a Account) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("id", a.ID),
+		slog.String("organisation_id", a.OrganisationID),
+		slog.String("type", a.Type),
+		slog.String("created_on", a.CreatedOn),
+		slog.String("modified_on", a.ModifiedOn),
+	}
+	if a.Version != nil {
+		attrs = append(attrs, slog.Int64("version", *a.Version))
+	}
+	if a.Attributes != nil {
+		attrs = append(attrs, slog.Any("attributes", *a.Attributes))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer, redacting the IBAN, account number and names of the account.
+func (a AccountAttributes) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("bank_id", a.BankID),
+		slog.String("bank_id_code", a.BankIDCode),
+		slog.String("bic", a.Bic),
+		slog.String("base_currency", a.BaseCurrency),
+		slog.String("account_number", mask(a.AccountNumber)),
+		slog.String("iban", mask(a.Iban)),
+		slog.String("name", maskNames(a.Name)),
+		slog.String("alternative_names", maskNames(a.AlternativeNames)),
+		slog.String("secondary_identification", mask(a.SecondaryIdentification)),
+	}
+	if a.Country != nil {
+		attrs = append(attrs, slog.String("country", *a.Country))
+	}
+	if a.Status != nil {
+		attrs = append(attrs, slog.String("status", *a.Status))
+	}
+	if a.StatusReason != nil {
+		attrs = append(attrs, slog.String("status_reason", *a.StatusReason))
+	}
+	if a.NameMatchingStatus != nil {
+		attrs = append(attrs, slog.String("name_matching_status", *a.NameMatchingStatus))
+	}
+	return slog.GroupValue(append(attrs, identificationAttrs(a.PrivateIdentification, a.OrganisationIdentification, a.UserDefinedData, a.UserDefinedInformation)...)...)
+}
+
+// identificationAttrs redacts the identification of the account holder and the user defined data of an account,
+// which might contain personal data, logging only whether they are set.
+func identificationAttrs(private *AccountPrivateIdentification, organisation *AccountOrganisationIdentification, data []AccountUserDefinedData, information *string) []slog.Attr {
+	var attrs []slog.Attr
+	if private != nil {
+		attrs = append(attrs, slog.String("private_identification", redacted))
+	}
+	if organisation != nil {
+		attrs = append(attrs, slog.String("organisation_identification", redacted))
+	}
+	if len(data) > 0 {
+		attrs = append(attrs, slog.String("user_defined_data", redacted))
+	}
+	if information != nil {
+		attrs = append(attrs, slog.String("user_defined_information", redacted))
+	}
+	return attrs
+}
+
+// LogValue implements slog.LogValuer, redacting the IBAN, account number and names of the account to create.
+func (a CreateAccountAttributes) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("bank_id", a.BankID),
+		slog.String("bank_id_code", a.BankIDCode),
+		slog.String("bic", a.Bic),
+		slog.String("country", a.Country),
+		slog.String("name", maskNames(a.Name)),
+	}
+	if a.AccountNumber != nil {
+		attrs = append(attrs, slog.String("account_number", mask(*a.AccountNumber)))
+	}
+	if a.Iban != nil {
+		attrs = append(attrs, slog.String("iban", mask(*a.Iban)))
+	}
+	if a.AlternativeNames != nil {
+		attrs = append(attrs, slog.String("alternative_names", maskNames(*a.AlternativeNames)))
+	}
+	if a.SecondaryIdentification != nil {
+		attrs = append(attrs, slog.String("secondary_identification", mask(*a.SecondaryIdentification)))
+	}
+	if a.BaseCurrency != nil {
+		attrs = append(attrs, slog.String("base_currency", *a.BaseCurrency))
+	}
+	return slog.GroupValue(append(attrs, identificationAttrs(a.PrivateIdentification, a.OrganisationIdentification, a.UserDefinedData, a.UserDefinedInformation)...)...)
+}
+
+// LogValue implements slog.LogValuer, redacting the names and secondary identification of the account update.
+func (a UpdateAccountAttributes) LogValue() slog.Value {
+	var attrs []slog.Attr
+	if a.AccountClassification != nil {
+		attrs = append(attrs, slog.String("account_classification", *a.AccountClassification))
+	}
+	if a.Name != nil {
+		attrs = append(attrs, slog.String("name", maskNames(*a.Name)))
+	}
+	if a.AlternativeNames != nil {
+		attrs = append(attrs, slog.String("alternative_names", maskNames(*a.AlternativeNames)))
+	}
+	if a.SecondaryIdentification != nil {
+		attrs = append(attrs, slog.String("secondary_identification", mask(*a.SecondaryIdentification)))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer, so the account data sent on creation is logged redacted.
+func (d CreateAccountData) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("id", d.ID),
+		slog.String("organisation_id", d.OrganisationID),
+		slog.String("type", d.Type),
+	}
+	if d.Attributes != nil {
+		attrs = append(attrs, slog.Any("attributes", *d.Attributes))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer, so the account data sent on update is logged redacted.
+func (d UpdateAccountData) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("id", d.ID),
+		slog.String("type", d.Type),
+		slog.Int64("version", d.Version),
+	}
+	if d.Attributes != nil {
+		attrs = append(attrs, slog.Any("attributes", *d.Attributes))
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,187 @@
+package form3
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_Do_Logger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error_message": "invalid version"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data": {"id": "10"}}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		call      func(client *Client)
+		wantLevel string
+		wantAttrs map[string]interface{}
+	}{
+		{
+			name: "Test successful request is logged at success level",
+			call: func(client *Client) {
+				client.Account.Create(context.Background(), "10", "", &CreateAccountAttributes{BankID: "202015", BankIDCode: "GBDSC", Bic: "BUKBGB22", Country: "GB", Iban: ToPointer("GB33BUKB20201555555555")})
+			},
+			wantLevel: "DEBUG",
+			wantAttrs: map[string]interface{}{"operation": "accounts.create", "method": "POST", "path": "/organisation/accounts", "status": 201.0, "attempt": 1.0, "request_id": "req-123"},
+		},
+		{
+			name: "Test client error is logged at client error level",
+			call: func(client *Client) {
+				client.Account.Delete(context.Background(), "10", 1)
+			},
+			wantLevel: "WARN",
+			wantAttrs: map[string]interface{}{"operation": "accounts.delete", "method": "DELETE", "path": "/organisation/accounts/10", "status": 409.0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			client, err := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithLogger(logger), WithHeader("Authorization", "Bearer secret-token"))
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			tt.call(client)
+
+			var entry map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("log entry - got = %s, error = %v", buf.String(), err)
+			}
+
+			if entry["level"] != tt.wantLevel {
+				t.Fatalf("log entry - level - got = %v, want %v", entry["level"], tt.wantLevel)
+			}
+			for key, want := range tt.wantAttrs {
+				if entry[key] != want {
+					t.Fatalf("log entry - %s - got = %v, want %v", key, entry[key], want)
+				}
+			}
+
+			if strings.Contains(buf.String(), "secret-token") || strings.Contains(buf.String(), "GB33BUKB") {
+				t.Fatalf("log entry - leaks sensitive data - got = %s", buf.String())
+			}
+		})
+	}
+}
+
+func TestClient_Do_Logger_TransportError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	client, err := NewClient(WithBaseURL(server.URL), WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	_, _, err = client.Account.List(context.Background(), &ListAccountsOptions{Iban: "GB11NWBK40030041426819"})
+	if err == nil {
+		t.Fatalf("AccountService.List() error = nil, want a transport error")
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log entry - got = %s, error = %v", buf.String(), err)
+	}
+	if entry["level"] != "ERROR" || !strings.Contains(entry["error"].(string), "/organisation/accounts") {
+		t.Fatalf("log entry - got = %s, want an error with the request path", buf.String())
+	}
+	if strings.Contains(buf.String(), "GB11NWBK") || strings.Contains(buf.String(), "filter") {
+		t.Fatalf("log entry - leaks the query - got = %s", buf.String())
+	}
+}
+
+func TestAccount_LogValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     interface{}
+		wantLog   []string
+		forbidden []string
+	}{
+		{
+			name: "Test Account",
+			value: Account{
+				ID:      "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc",
+				Version: ToPointer(int64(0)),
+				Attributes: &AccountAttributes{
+					AccountNumber:    "41426819",
+					Iban:             "GB11NWBK40030041426819",
+					Name:             []string{"Samantha Holder"},
+					AlternativeNames: []string{"Sam Holder"},
+					Country:          ToPointer("GB"),
+					PrivateIdentification: &AccountPrivateIdentification{
+						BirthDate:      "2017-07-23",
+						Identification: "13YH458762",
+					},
+					UserDefinedInformation: ToPointer("Some important info"),
+				},
+			},
+			wantLog:   []string{"ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", "****6819", "GB", "REDACTED", "private_identification"},
+			forbidden: []string{"41426819", "GB11NWBK40030041426819", "Samantha", "Sam Holder", "2017-07-23", "13YH458762", "Some important info"},
+		},
+		{
+			name: "Test CreateAccountAttributes",
+			value: CreateAccountAttributes{
+				Country:                 "GB",
+				AccountNumber:           ToPointer("41426819"),
+				Iban:                    ToPointer("GB11NWBK40030041426819"),
+				Name:                    []string{"Samantha Holder"},
+				SecondaryIdentification: ToPointer("A1B2C3D4"),
+				OrganisationIdentification: &AccountOrganisationIdentification{
+					Identification: "123654",
+					Actors:         []AccountOrganisationActor{{Name: []string{"Jeff Page"}}},
+				},
+			},
+			wantLog:   []string{"****6819", "GB", "REDACTED", "organisation_identification"},
+			forbidden: []string{"41426819", "GB11NWBK40030041426819", "Samantha", "A1B2C3D4", "123654", "Jeff Page"},
+		},
+		{
+			name: "Test UpdateAccountAttributes",
+			value: UpdateAccountAttributes{
+				AccountClassification:   ToPointer("Business"),
+				Name:                    &[]string{"Samantha Holder"},
+				AlternativeNames:        &[]string{"Sam Holder"},
+				SecondaryIdentification: ToPointer("A1B2C3D4"),
+			},
+			wantLog:   []string{"Business", "REDACTED", "****C3D4"},
+			forbidden: []string{"Samantha", "Sam Holder", "A1B2C3D4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			slog.New(slog.NewTextHandler(&buf, nil)).Info("account", "account", tt.value)
+
+			for _, want := range tt.wantLog {
+				if !strings.Contains(buf.String(), want) {
+					t.Fatalf("LogValue() - got = %s, want it to contain %v", buf.String(), want)
+				}
+			}
+			for _, forbidden := range tt.forbidden {
+				if strings.Contains(buf.String(), forbidden) {
+					t.Fatalf("LogValue() - got = %s, want it not to contain %v", buf.String(), forbidden)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package form3
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metricsNamespace is the namespace of the Prometheus metrics.
+const metricsNamespace = "form3_client"
+
+// statusClassError is the status class of requests that failed without response.
+const statusClassError = "error"
+
+// MetricsRecorder records metrics about the requests sent by the client. Implementations must be safe for concurrent use.
+type MetricsRecorder interface {
+	// ObserveRequest records a finished HTTP attempt. statusClass is "2xx", "4xx", "5xx", etc. or "error"
+	// when the request failed without response.
+	ObserveRequest(operation, method, statusClass string, duration time.Duration)
+
+	// AddInFlight adds delta to the number of HTTP attempts in flight.
+	AddInFlight(operation, method string, delta int)
+
+	// IncRetry records that a failed request is going to be retried.
+	IncRetry(operation, method string)
+
+	// ObserveRateLimitWait records how long a request waited for the rate limiter.
+	ObserveRateLimitWait(operation, method string, wait time.Duration)
+}
+
+// noopMetrics is the MetricsRecorder used when none is configured.
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveRequest(operation, method, statusClass string, duration time.Duration) {}
+func (noopMetrics) AddInFlight(operation, method string, delta int)                               {}
+func (noopMetrics) IncRetry(operation, method string)                                             {}
+func (noopMetrics) ObserveRateLimitWait(operation, method string, wait time.Duration)             {}
+
+// metrics returns the configured metrics recorder, or a no-op one if none is configured.
+func (c *Client) metrics() MetricsRecorder {
+	if c.Metrics == nil {
+		return noopMetrics{}
+	}
+	return c.Metrics
+}
+
+// PrometheusMetrics is a MetricsRecorder exposing the client metrics to Prometheus.
+type PrometheusMetrics struct {
+	requests      *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	inFlight      *prometheus.GaugeVec
+	retries       *prometheus.CounterVec
+	rateLimitWait *prometheus.HistogramVec
+	unknownFields *prometheus.CounterVec
+}
+
+// NewPrometheusMetrics returns a MetricsRecorder whose metrics are registered with the given registerer.
+// prometheus.DefaultRegisterer is used if nil.
+func NewPrometheusMetrics(registerer prometheus.Registerer) (*PrometheusMetrics, error) {
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+
+	labels := []string{"operation", "method"}
+	m := &PrometheusMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "requests_total",
+			Help:      "Number of HTTP requests sent to the Form3 API, including retries.",
+		}, append(labels, "status_class")),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of the HTTP requests sent to the Form3 API.",
+			Buckets:   prometheus.DefBuckets,
+		}, append(labels, "status_class")),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "requests_in_flight",
+			Help:      "Number of HTTP requests to the Form3 API waiting for a response.",
+		}, labels),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "retries_total",
+			Help:      "Number of failed requests to the Form3 API that were retried.",
+		}, labels),
+		rateLimitWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "rate_limit_wait_seconds",
+			Help:      "Time requests to the Form3 API waited for the client-side rate limiter.",
+			Buckets:   []float64{0, .005, .01, .05, .1, .5, 1, 5, 10, 30},
+		}, labels),
+		unknownFields: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "unknown_fields_total",
+			Help:      "Number of response bodies from the Form3 API with a field unknown to the client.",
+		}, []string{"operation", "field"}),
+	}
+
+	for _, collector := range []prometheus.Collector{m.requests, m.duration, m.inFlight, m.retries, m.rateLimitWait, m.unknownFields} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, fmt.Errorf("failed to register metrics: %w", err)
+		}
+	}
+
+	return m, nil
+}
+
+// ObserveRequest implements MetricsRecorder.
+func (m *PrometheusMetrics) ObserveRequest(operation, method, statusClass string, duration time.Duration) {
+	m.requests.WithLabelValues(operation, method, statusClass).Inc()
+	m.duration.WithLabelValues(operation, method, statusClass).Observe(duration.Seconds())
+}
+
+// AddInFlight implements MetricsRecorder.
+func (m *PrometheusMetrics) AddInFlight(operation, method string, delta int) {
+	m.inFlight.WithLabelValues(operation, method).Add(float64(delta))
+}
+
+// IncRetry implements MetricsRecorder.
+func (m *PrometheusMetrics) IncRetry(operation, method string) {
+	m.retries.WithLabelValues(operation, method).Inc()
+}
+
+// ObserveRateLimitWait implements MetricsRecorder.
+func (m *PrometheusMetrics) ObserveRateLimitWait(operation, method string, wait time.Duration) {
+	m.rateLimitWait.WithLabelValues(operation, method).Observe(wait.Seconds())
+}
+
+// ObserveUnknownFields counts the fields unknown to the client found in a response body. It can be given to
+// WithUnknownFieldsHandler.
+func (m *PrometheusMetrics) ObserveUnknownFields(operation string, paths []string) {
+	if operation == "" {
+		operation = "request"
+	}
+	for _, path := range paths {
+		m.unknownFields.WithLabelValues(operation, path).Inc()
+	}
+}
+
+// statusClass returns the class of the response status code, such as "2xx", or "error" if there is no response.
+func statusClass(res *http.Response) string {
+	if res == nil {
+		return statusClassError
+	}
+	return strconv.Itoa(res.StatusCode/100) + "xx"
+}
+
+// metricsOperation returns the operation label of the request. Requests sent with Client.Do have no operation.
+func metricsOperation(r *Request) string {
+	if r.Operation == "" {
+		return "request"
+	}
+	return r.Operation
+}
@@ -0,0 +1,86 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestClient_Do_Metrics(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"data": {"id": "10"}}`))
+	}))
+	defer server.Close()
+
+	registry := prometheus.NewRegistry()
+	metrics, err := NewPrometheusMetrics(registry)
+	if err != nil {
+		t.Fatalf("NewPrometheusMetrics() error = %v", err)
+	}
+
+	client, err := NewClient(
+		WithBaseURL(server.URL),
+		WithHTTPClient(server.Client()),
+		WithRetryPolicy(testRetryPolicy(2)),
+		WithRateLimiter(NewRateLimiter(100, 10)),
+		WithMetrics(metrics),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if _, _, err := client.Account.Fetch(context.Background(), "10"); err != nil {
+		t.Fatalf("AccountService.Fetch() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		collector prometheus.Collector
+		labels    []string
+		want      float64
+	}{
+		{name: "Test failed attempt is counted", collector: metrics.requests, labels: []string{"accounts.fetch", "GET", "5xx"}, want: 1},
+		{name: "Test successful attempt is counted", collector: metrics.requests, labels: []string{"accounts.fetch", "GET", "2xx"}, want: 1},
+		{name: "Test retry is counted", collector: metrics.retries, labels: []string{"accounts.fetch", "GET"}, want: 1},
+		{name: "Test no request is left in flight", collector: metrics.inFlight, labels: []string{"accounts.fetch", "GET"}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got float64
+			switch collector := tt.collector.(type) {
+			case *prometheus.CounterVec:
+				got = testutil.ToFloat64(collector.WithLabelValues(tt.labels...))
+			case *prometheus.GaugeVec:
+				got = testutil.ToFloat64(collector.WithLabelValues(tt.labels...))
+			}
+
+			if got != tt.want {
+				t.Fatalf("metric %v - got = %v, want %v", tt.labels, got, tt.want)
+			}
+		})
+	}
+
+	// Both histograms have one series per label combination observed.
+	if got := testutil.CollectAndCount(metrics.duration); got != 2 {
+		t.Fatalf("request_duration_seconds - series - got = %v, want %v", got, 2)
+	}
+	if got := testutil.CollectAndCount(metrics.rateLimitWait); got != 1 {
+		t.Fatalf("rate_limit_wait_seconds - series - got = %v, want %v", got, 1)
+	}
+
+	// Metrics cannot be registered twice in the same registry.
+	if _, err := NewPrometheusMetrics(registry); err == nil {
+		t.Fatalf("NewPrometheusMetrics() error = %v, want an error", err)
+	}
+}
@@ -0,0 +1,61 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+)
+
+// Request is a logical request to the Form3 API, before it is turned into one or more HTTP requests.
+type Request struct {
+	// Operation is the logical name of the operation, for example "accounts.create".
+	// It is empty for requests sent directly with Client.Do.
+	Operation string
+
+	// Method is the HTTP method of the request.
+	Method string
+
+	// URL is the path and query of the request, resolved against the client base URL.
+	URL string
+
+	// Template is the URL path with placeholders instead of resource IDs, for example "organisation/accounts/{id}".
+	// It is used in traces and metrics to keep their cardinality low.
+	Template string
+
+	// Header contains the headers specific to the request, such as If-None-Match, if any.
+	Header http.Header
+
+	// Body is the value marshalled as the request body, if any.
+	Body interface{}
+
+	// Result is the value the response body is decoded into, if any.
+	Result interface{}
+}
+
+// Handler sends a logical request to the Form3 API and decodes the response body into req.Result.
+// The response is returned whenever the API answered, and failed requests return a *Form3APIError
+// when the API answered with an error.
+type Handler func(ctx context.Context, req *Request) (*Response, error)
+
+// Middleware wraps a Handler to add behaviour around every request sent by the client.
+type Middleware func(next Handler) Handler
+
+// Use registers middlewares around every request sent by the client.
+// Middlewares run in the order they are registered, so the first one is the outermost.
+func (c *Client) Use(middlewares ...Middleware) {
+	c.middlewares = append(c.middlewares, middlewares...)
+}
+
+// do sends the logical request through the middleware chain, within a span named after its operation.
+func (c *Client) do(ctx context.Context, req *Request) (*Response, error) {
+	ctx, span := c.startOperationSpan(ctx, req)
+
+	handler := Handler(c.send)
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		handler = c.middlewares[i](handler)
+	}
+
+	res, err := handler(ctx, req)
+	endOperationSpan(span, req, res, err)
+
+	return res, err
+}
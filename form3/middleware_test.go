@@ -0,0 +1,98 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClient_Use(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/organisation/accounts/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_message": "record missing does not exist"}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"id": "10", "version": 3}}`))
+	}))
+	defer server.Close()
+
+	var calls []string
+	recorder := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, req *Request) (*Response, error) {
+				calls = append(calls, name+" before "+req.Operation)
+				res, err := next(ctx, req)
+
+				var apiErr *Form3APIError
+				if errors.As(err, &apiErr) {
+					calls = append(calls, name+" after "+apiErr.Message)
+				} else if body, ok := req.Result.(*FetchAccountResponse); ok {
+					calls = append(calls, name+" after "+body.Data.ID)
+				}
+				return res, err
+			}
+		}
+	}
+
+	client, err := NewClient(WithBaseURL(server.URL+"/v1/"), WithHTTPClient(server.Client()), WithMiddleware(recorder("first")))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	client.Use(recorder("second"))
+
+	tests := []struct {
+		name      string
+		accountID string
+		wantCalls []string
+	}{
+		{
+			name:      "Test middlewares see the operation and the decoded result",
+			accountID: "10",
+			wantCalls: []string{"first before accounts.fetch", "second before accounts.fetch", "second after 10", "first after 10"},
+		},
+		{
+			name:      "Test middlewares see the decoded API error",
+			accountID: "missing",
+			wantCalls: []string{"first before accounts.fetch", "second before accounts.fetch", "second after record missing does not exist", "first after record missing does not exist"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			client.Account.Fetch(context.Background(), tt.accountID)
+
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Fatalf("Client.Use() - calls - got = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestClient_Use_ShortCircuit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL)
+	}))
+	defer server.Close()
+
+	errBlocked := errors.New("blocked")
+	client, err := NewClient(WithBaseURL(server.URL), WithMiddleware(func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			if req.Method == http.MethodDelete {
+				return nil, errBlocked
+			}
+			return next(ctx, req)
+		}
+	}))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if _, err := client.Account.Delete(context.Background(), "10", 0); !errors.Is(err, errBlocked) {
+		t.Fatalf("AccountService.Delete() error - got = %v, want %v", err, errBlocked)
+	}
+}
@@ -0,0 +1,341 @@
+package form3
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Environment variables read by NewClientFromEnv.
+const (
+	EnvBaseURL          = "FORM3_API_BASE_URL"
+	EnvOrganisationID   = "FORM3_ORGANISATION_ID"
+	EnvTimeout          = "FORM3_TIMEOUT"
+	EnvUserAgent        = "FORM3_USER_AGENT"
+	EnvRetryMaxAttempts = "FORM3_RETRY_MAX_ATTEMPTS"
+)
+
+// uuidRegexp matches the UUIDs used by Form3 as resource and organisation IDs.
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Option configures a Client created with NewClient.
+type Option func(*Client) error
+
+// WithBaseURL sets the base URL of the Form3 API, for example "http://localhost:8080/v1/".
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) error {
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("invalid base URL %q: it must be an absolute http or https URL", baseURL)
+		}
+
+		// Without a trailing slash, the last path segment would be dropped when resolving request paths.
+		if !strings.HasSuffix(u.Path, "/") {
+			u.Path += "/"
+		}
+
+		c.BaseURL = u
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to make requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) error {
+		if httpClient == nil {
+			return errors.New("invalid HTTP client: it must not be nil")
+		}
+
+		c.client = httpClient
+		return nil
+	}
+}
+
+// WithTimeout sets the timeout of every HTTP request, including reading the response body.
+// The HTTP client given with WithHTTPClient is copied, not modified.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		if timeout <= 0 {
+			return fmt.Errorf("invalid timeout %s: it must be positive", timeout)
+		}
+
+		c.timeout = timeout
+		return nil
+	}
+}
+
+// WithMaxResponseSize sets the maximum size in bytes of a response body. Larger bodies return ErrResponseTooLarge.
+func WithMaxResponseSize(size int64) Option {
+	return func(c *Client) error {
+		if size <= 0 {
+			return fmt.Errorf("invalid max response size %d: it must be positive", size)
+		}
+
+		c.MaxResponseSize = size
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) error {
+		if userAgent == "" {
+			return errors.New("invalid user agent: it must not be empty")
+		}
+
+		c.UserAgent = userAgent
+		return nil
+	}
+}
+
+// WithHeader adds a header sent with every request.
+func WithHeader(key, value string) Option {
+	return func(c *Client) error {
+		if key == "" {
+			return errors.New("invalid header: the key must not be empty")
+		}
+
+		c.Header.Add(key, value)
+		return nil
+	}
+}
+
+// WithOrganisationID sets the organisation ID used when none is given to a service method.
+func WithOrganisationID(organisationID string) Option {
+	return func(c *Client) error {
+		if !uuidRegexp.MatchString(organisationID) {
+			return fmt.Errorf("invalid organisation ID %q: it must be a UUID", organisationID)
+		}
+
+		c.OrganisationID = organisationID
+		return nil
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry failed requests.
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(c *Client) error {
+		if policy != nil && (policy.BaseDelay < 0 || policy.MaxDelay < 0 || policy.Jitter < 0 || policy.Jitter > 1) {
+			return errors.New("invalid retry policy: delays must not be negative and jitter must be between 0 and 1")
+		}
+
+		c.RetryPolicy = policy
+		return nil
+	}
+}
+
+// WithVersionRetry sets how many times operations needing the latest version of a resource, such as
+// AccountService.DeleteLatest, fetch it again after a version conflict.
+func WithVersionRetry(maxAttempts int) Option {
+	return func(c *Client) error {
+		if maxAttempts < 1 {
+			return fmt.Errorf("invalid version retry attempts %d: it must be positive", maxAttempts)
+		}
+
+		c.VersionRetry = &VersionRetryPolicy{MaxAttempts: maxAttempts}
+		return nil
+	}
+}
+
+// WithRateLimiter sets the rate limiter used to throttle requests.
+func WithRateLimiter(rateLimiter *RateLimiter) Option {
+	return func(c *Client) error {
+		c.RateLimiter = rateLimiter
+		return nil
+	}
+}
+
+// WithCache sets the cache storing the fetched resources, such as accounts, and how long they stay fresh.
+// An in-memory LRU cache holding 1000 resources is used if cache is nil.
+func WithCache(cache Cache, ttl time.Duration) Option {
+	return func(c *Client) error {
+		if ttl <= 0 {
+			return fmt.Errorf("invalid cache TTL %s: it must be positive", ttl)
+		}
+		if cache == nil {
+			cache = NewLRUCache(defaultCacheSize)
+		}
+
+		c.Cache = cache
+		c.CacheTTL = ttl
+		return nil
+	}
+}
+
+// WithHedging sets the policy used to hedge slow GET requests.
+func WithHedging(policy *HedgePolicy) Option {
+	return func(c *Client) error {
+		if policy != nil && (policy.Percentile < 0 || policy.Percentile > 1) {
+			return fmt.Errorf("invalid hedging percentile %v: it must be between 0 and 1", policy.Percentile)
+		}
+		if policy != nil && (policy.MaxHedgeRate < 0 || policy.MaxHedgeRate > 1) {
+			return fmt.Errorf("invalid max hedge rate %v: it must be between 0 and 1", policy.MaxHedgeRate)
+		}
+
+		c.Hedging = policy
+		return nil
+	}
+}
+
+// WithCircuitBreaker sets the circuit breaker which stops sending requests for a while after consecutive failures.
+func WithCircuitBreaker(cb *CircuitBreaker) Option {
+	return func(c *Client) error {
+		c.CircuitBreaker = cb
+		return nil
+	}
+}
+
+// WithAuthenticator sets the authenticator used to add credentials to every request.
+func WithAuthenticator(authenticator Authenticator) Option {
+	return func(c *Client) error {
+		c.Authenticator = authenticator
+		return nil
+	}
+}
+
+// WithSigner sets the signer used to sign every request.
+func WithSigner(signer Signer) Option {
+	return func(c *Client) error {
+		c.Signer = signer
+		return nil
+	}
+}
+
+// WithMiddleware registers middlewares around every request sent by the client, the first one being the outermost.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *Client) error {
+		c.Use(middlewares...)
+		return nil
+	}
+}
+
+// WithLogger sets the logger used to log every request attempt.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) error {
+		c.Logger = logger
+		return nil
+	}
+}
+
+// WithLogLevels sets the levels used to log requests depending on their outcome.
+func WithLogLevels(levels LogLevels) Option {
+	return func(c *Client) error {
+		c.LogLevels = levels
+		return nil
+	}
+}
+
+// WithTracerProvider sets the OpenTelemetry tracer provider used to create the spans of every request.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *Client) error {
+		c.TracerProvider = provider
+		return nil
+	}
+}
+
+// WithPropagator sets the OpenTelemetry propagator used to inject the trace context in the request headers.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *Client) error {
+		c.Propagator = propagator
+		return nil
+	}
+}
+
+// WithMetrics sets the recorder of the metrics about every request, for example one created with NewPrometheusMetrics.
+func WithMetrics(metrics MetricsRecorder) Option {
+	return func(c *Client) error {
+		c.Metrics = metrics
+		return nil
+	}
+}
+
+// WithStrictDecoding makes response bodies with fields unknown to the client fail with an UnknownFieldsError.
+// It is meant for contract tests.
+func WithStrictDecoding() Option {
+	return func(c *Client) error {
+		c.StrictDecoding = true
+		return nil
+	}
+}
+
+// WithUnknownFieldsHandler sets the function called with the paths of the fields unknown to the client found in
+// a response body, for example PrometheusMetrics.ObserveUnknownFields.
+func WithUnknownFieldsHandler(handler func(operation string, paths []string)) Option {
+	return func(c *Client) error {
+		c.UnknownFieldsHandler = handler
+		return nil
+	}
+}
+
+// WithLegacyTimeoutErrors makes client side timeouts return a Form3APIError with a 504 status code, as previous
+// versions did, instead of a TimeoutError.
+func WithLegacyTimeoutErrors() Option {
+	return func(c *Client) error {
+		c.LegacyTimeoutErrors = true
+		return nil
+	}
+}
+
+// NewClientFromEnv returns a new Form3 API client configured from the FORM3_* environment variables.
+// The given options are applied after the environment, so they take precedence.
+func NewClientFromEnv(opts ...Option) (*Client, error) {
+	var (
+		envOpts []Option
+		errs    []error
+	)
+
+	if baseURL, ok := os.LookupEnv(EnvBaseURL); ok {
+		envOpts = append(envOpts, WithBaseURL(baseURL))
+	}
+
+	if organisationID, ok := os.LookupEnv(EnvOrganisationID); ok {
+		envOpts = append(envOpts, WithOrganisationID(organisationID))
+	}
+
+	if timeout, ok := os.LookupEnv(EnvTimeout); ok {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s: %w", EnvTimeout, err))
+		} else {
+			envOpts = append(envOpts, WithTimeout(d))
+		}
+	}
+
+	if userAgent, ok := os.LookupEnv(EnvUserAgent); ok {
+		envOpts = append(envOpts, WithUserAgent(userAgent))
+	}
+
+	if maxAttempts, ok := os.LookupEnv(EnvRetryMaxAttempts); ok {
+		n, err := strconv.Atoi(maxAttempts)
+		if err != nil || n < 1 {
+			errs = append(errs, fmt.Errorf("invalid %s %q: it must be a positive integer", EnvRetryMaxAttempts, maxAttempts))
+		} else {
+			policy := DefaultRetryPolicy()
+			policy.MaxAttempts = n
+			envOpts = append(envOpts, WithRetryPolicy(policy))
+		}
+	}
+
+	client, err := NewClient(append(envOpts, opts...)...)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to configure client from environment: %w", errors.Join(errs...))
+	}
+
+	return client, nil
+}
@@ -0,0 +1,191 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClient(t *testing.T) {
+	httpClient := &http.Client{}
+
+	tests := []struct {
+		name        string
+		opts        []Option
+		wantBaseURL string
+		wantTimeout time.Duration
+		wantErrs    []string
+	}{
+		{
+			name:        "Test default client",
+			opts:        nil,
+			wantBaseURL: defaultBaseURL,
+		},
+		{
+			name:        "Test nil option is ignored",
+			opts:        []Option{nil},
+			wantBaseURL: defaultBaseURL,
+		},
+		{
+			name:        "Test base URL without trailing slash",
+			opts:        []Option{WithBaseURL("http://localhost:8080/v1")},
+			wantBaseURL: "http://localhost:8080/v1/",
+		},
+		{
+			name:        "Test timeout on custom HTTP client",
+			opts:        []Option{WithHTTPClient(httpClient), WithTimeout(2 * time.Second)},
+			wantBaseURL: defaultBaseURL,
+			wantTimeout: 2 * time.Second,
+		},
+		{
+			name: "Test every invalid option is reported",
+			opts: []Option{
+				WithBaseURL("localhost:8080"),
+				WithTimeout(-time.Second),
+				WithOrganisationID("1234"),
+				WithUserAgent(""),
+				WithMaxResponseSize(0),
+				WithHedging(&HedgePolicy{Delay: time.Millisecond, MaxHedgeRate: -1}),
+			},
+			wantErrs: []string{"invalid base URL", "invalid timeout", "invalid organisation ID", "invalid user agent", "invalid max response size", "invalid max hedge rate"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(tt.opts...)
+			if len(tt.wantErrs) > 0 {
+				if err == nil {
+					t.Fatalf("NewClient() error = %v, wantErr %v", err, tt.wantErrs)
+				}
+				for _, wantErr := range tt.wantErrs {
+					if !strings.Contains(err.Error(), wantErr) {
+						t.Fatalf("NewClient() error - got = %v, want it to contain %v", err, wantErr)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			if client.BaseURL.String() != tt.wantBaseURL {
+				t.Fatalf("NewClient() - BaseURL - got = %v, want %v", client.BaseURL, tt.wantBaseURL)
+			}
+
+			if client.client.Timeout != tt.wantTimeout {
+				t.Fatalf("NewClient() - Timeout - got = %v, want %v", client.client.Timeout, tt.wantTimeout)
+			}
+
+			if client.Account == nil {
+				t.Fatalf("NewClient() - Account service is nil")
+			}
+		})
+	}
+
+	// The given HTTP client must not be modified.
+	if httpClient.Timeout != 0 {
+		t.Fatalf("NewClient() - HTTP client timeout modified - got = %v, want %v", httpClient.Timeout, 0)
+	}
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		wantErrs []string
+	}{
+		{
+			name: "Test valid environment",
+			env: map[string]string{
+				EnvBaseURL:          "http://localhost:8080/v1/",
+				EnvOrganisationID:   "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+				EnvTimeout:          "5s",
+				EnvUserAgent:        "my-service",
+				EnvRetryMaxAttempts: "4",
+			},
+		},
+		{
+			name: "Test invalid environment",
+			env: map[string]string{
+				EnvBaseURL:          "://localhost",
+				EnvTimeout:          "five seconds",
+				EnvRetryMaxAttempts: "0",
+			},
+			wantErrs: []string{"invalid base URL", EnvTimeout, EnvRetryMaxAttempts},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			client, err := NewClientFromEnv()
+			if len(tt.wantErrs) > 0 {
+				if err == nil {
+					t.Fatalf("NewClientFromEnv() error = %v, wantErr %v", err, tt.wantErrs)
+				}
+				for _, wantErr := range tt.wantErrs {
+					if !strings.Contains(err.Error(), wantErr) {
+						t.Fatalf("NewClientFromEnv() error - got = %v, want it to contain %v", err, wantErr)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewClientFromEnv() error = %v", err)
+			}
+
+			if client.BaseURL.String() != tt.env[EnvBaseURL] {
+				t.Fatalf("NewClientFromEnv() - BaseURL - got = %v, want %v", client.BaseURL, tt.env[EnvBaseURL])
+			}
+			if client.OrganisationID != tt.env[EnvOrganisationID] {
+				t.Fatalf("NewClientFromEnv() - OrganisationID - got = %v, want %v", client.OrganisationID, tt.env[EnvOrganisationID])
+			}
+			if client.client.Timeout != 5*time.Second {
+				t.Fatalf("NewClientFromEnv() - Timeout - got = %v, want %v", client.client.Timeout, 5*time.Second)
+			}
+			if client.UserAgent != tt.env[EnvUserAgent] {
+				t.Fatalf("NewClientFromEnv() - UserAgent - got = %v, want %v", client.UserAgent, tt.env[EnvUserAgent])
+			}
+			if client.RetryPolicy == nil || client.RetryPolicy.MaxAttempts != 4 {
+				t.Fatalf("NewClientFromEnv() - RetryPolicy - got = %+v, want %v max attempts", client.RetryPolicy, 4)
+			}
+		})
+	}
+}
+
+func TestClient_Do_DefaultHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("User-Agent"); got != "my-service" {
+			t.Errorf("User-Agent - got = %v, want %v", got, "my-service")
+		}
+		if got := r.Header.Get("X-Tenant"); got != "acme" {
+			t.Errorf("X-Tenant - got = %v, want %v", got, "acme")
+		}
+		if got := r.URL.Path; got != "/v1/organisation/accounts/10" {
+			t.Errorf("Path - got = %v, want %v", got, "/v1/organisation/accounts/10")
+		}
+		w.Write([]byte(`{"data": {"id": "10"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithBaseURL(server.URL+"/v1"),
+		WithHTTPClient(server.Client()),
+		WithUserAgent("my-service"),
+		WithHeader("X-Tenant", "acme"),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if _, _, err := client.Account.Fetch(context.Background(), "10"); err != nil {
+		t.Fatalf("AccountService.Fetch() error = %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package form3
+
+import (
+	"context"
+	"iter"
+	"net/http"
+)
+
+// Pager iterates over the pages of a list endpoint of the Form3 API by following the Links.Next of every page.
+// Pages are requested like any other request, so they go through the client middlewares, retries and rate limiter.
+// A Pager is not safe for concurrent use.
+type Pager[T any] struct {
+	client    *Client
+	operation string
+	template  string
+
+	// next is the URL of the next page, empty once the last page was fetched.
+	next string
+
+	// Prefetch is the number of pages fetched ahead of the consumer by All. Pages are fetched on demand if zero.
+	Prefetch int
+}
+
+// NewPager returns a pager starting at the given URL. The operation and URL template are the ones of the list
+// request, as seen by middlewares, traces and metrics.
+func NewPager[T any](client *Client, operation, url, template string) *Pager[T] {
+	return &Pager[T]{client: client, operation: operation, template: template, next: url}
+}
+
+// More reports whether there are pages left.
+func (p *Pager[T]) More() bool {
+	return p.next != ""
+}
+
+// NextPage fetches the next page and returns its items. It returns no items and no error if there are no pages left.
+func (p *Pager[T]) NextPage(ctx context.Context) ([]T, *Response, error) {
+	if !p.More() {
+		return nil, nil, nil
+	}
+
+	page := Form3BodyResponse[[]T]{}
+	res, err := p.client.do(ctx, &Request{
+		Operation: p.operation,
+		Method:    http.MethodGet,
+		URL:       p.next,
+		Template:  p.template,
+		Result:    &page,
+	})
+	if err != nil {
+		return nil, res, err
+	}
+
+	// An empty page is the last one, even if it links to another one.
+	p.next = page.Links.Next
+	if len(page.Data) == 0 {
+		p.next = ""
+	}
+
+	return page.Data, res, nil
+}
+
+// pagerPage is a page fetched ahead of the consumer.
+type pagerPage[T any] struct {
+	items []T
+	err   error
+}
+
+// All returns an iterator over the items of every page left. Iteration stops at the first error, which is yielded,
+// and no more pages are fetched once the consumer stops iterating.
+func (p *Pager[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		if p.Prefetch <= 0 {
+			for p.More() {
+				items, _, err := p.NextPage(ctx)
+				if !yieldPage(yield, items, err) {
+					return
+				}
+			}
+			return
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		pages := make(chan pagerPage[T], p.Prefetch)
+		defer func() {
+			// The pager must not be used by the prefetching goroutine once the iteration is over.
+			cancel()
+			for range pages {
+			}
+		}()
+
+		go func() {
+			defer close(pages)
+			for p.More() {
+				items, _, err := p.NextPage(ctx)
+				select {
+				case pages <- pagerPage[T]{items: items, err: err}:
+				case <-ctx.Done():
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+		}()
+
+		for page := range pages {
+			if !yieldPage(yield, page.items, page.err) {
+				return
+			}
+		}
+	}
+}
+
+// yieldPage yields the items of a page, or its error. It reports whether the iteration goes on.
+func yieldPage[T any](yield func(T, error) bool, items []T, err error) bool {
+	if err != nil {
+		var zero T
+		yield(zero, err)
+		return false
+	}
+
+	for _, item := range items {
+		if !yield(item, nil) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,174 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+// newPagesServer returns a server listing pages of two accounts, linking every page to the next one up to the last.
+func newPagesServer(t *testing.T, pages int, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		number, _ := strconv.Atoi(r.URL.Query().Get("page[number]"))
+		if number >= pages {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		next := ""
+		if number < pages-1 {
+			next = fmt.Sprintf("/v1/organisation/accounts?page[number]=%d&page[size]=2", number+1)
+		}
+		fmt.Fprintf(w, `{"data": [{"id": "%d-0"}, {"id": "%d-1"}], "links": {"next": %q}}`, number, number, next)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestPager_All(t *testing.T) {
+	tests := []struct {
+		name         string
+		pages        int
+		prefetch     int
+		stopAfter    int
+		wantIDs      int
+		wantRequests int32
+	}{
+		{
+			name:         "Test every page is fetched",
+			pages:        3,
+			wantIDs:      6,
+			wantRequests: 3,
+		},
+		{
+			name:         "Test breaking early stops fetching pages",
+			pages:        3,
+			stopAfter:    3,
+			wantIDs:      3,
+			wantRequests: 2,
+		},
+		{
+			name:         "Test every page is fetched with prefetch",
+			pages:        5,
+			prefetch:     2,
+			wantIDs:      10,
+			wantRequests: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := newPagesServer(t, tt.pages, &requests)
+
+			client, err := NewClient(WithBaseURL(server.URL+"/v1/"), WithHTTPClient(server.Client()))
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			pager := client.Account.ListPager(&ListAccountsOptions{PageSize: 2})
+			pager.Prefetch = tt.prefetch
+
+			var ids []string
+			for account, err := range pager.All(context.Background()) {
+				if err != nil {
+					t.Fatalf("Pager.All() error = %v", err)
+				}
+				ids = append(ids, account.ID)
+				if len(ids) == tt.stopAfter {
+					break
+				}
+			}
+
+			if len(ids) != tt.wantIDs {
+				t.Fatalf("Pager.All() - accounts - got = %v, want %d accounts", ids, tt.wantIDs)
+			}
+			if ids[0] != "0-0" || ids[len(ids)-1] != fmt.Sprintf("%d-%d", (tt.wantIDs-1)/2, (tt.wantIDs-1)%2) {
+				t.Fatalf("Pager.All() - accounts - got = %v, want accounts in page order", ids)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Fatalf("Pager.All() - requests - got = %v, want %v", got, tt.wantRequests)
+			}
+			if tt.stopAfter == 0 && pager.More() {
+				t.Fatalf("Pager.More() - got = true, want false after the last page")
+			}
+		})
+	}
+}
+
+func TestPager_All_Error(t *testing.T) {
+	for _, prefetch := range []int{0, 2} {
+		t.Run(fmt.Sprintf("Test prefetch %d", prefetch), func(t *testing.T) {
+			var requests atomic.Int32
+			server := newPagesServer(t, 2, &requests)
+
+			client, err := NewClient(WithBaseURL(server.URL+"/v1/"), WithHTTPClient(server.Client()))
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			// The pages after the last one fail.
+			pager := NewPager[Account](client, OperationListAccounts, "organisation/accounts?page[number]=2", defaultAccountsPath)
+			pager.Prefetch = prefetch
+
+			var (
+				ids     []string
+				iterErr error
+			)
+			for account, err := range pager.All(context.Background()) {
+				if err != nil {
+					iterErr = err
+					continue
+				}
+				ids = append(ids, account.ID)
+			}
+
+			if len(ids) != 0 {
+				t.Fatalf("Pager.All() - accounts - got = %v, want none", ids)
+			}
+			if !errors.Is(iterErr, ErrServer) {
+				t.Fatalf("Pager.All() error = %v, want %v", iterErr, ErrServer)
+			}
+		})
+	}
+}
+
+func TestPager_NextPage(t *testing.T) {
+	var requests atomic.Int32
+	server := newPagesServer(t, 2, &requests)
+
+	client, err := NewClient(WithBaseURL(server.URL+"/v1/"), WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	pager := client.Account.ListPager(nil)
+	for page := 0; page < 2; page++ {
+		if !pager.More() {
+			t.Fatalf("Pager.More() - page %d - got = false, want true", page)
+		}
+
+		accounts, res, err := pager.NextPage(context.Background())
+		if err != nil {
+			t.Fatalf("Pager.NextPage() error = %v", err)
+		}
+		if len(accounts) != 2 || res.StatusCode != http.StatusOK {
+			t.Fatalf("Pager.NextPage() - page %d - got = %+v, %v, want 2 accounts", page, accounts, res.StatusCode)
+		}
+	}
+
+	accounts, res, err := pager.NextPage(context.Background())
+	if pager.More() || accounts != nil || res != nil || err != nil {
+		t.Fatalf("Pager.NextPage() - after the last page - got = %v, %v, %v, want nothing", accounts, res, err)
+	}
+}
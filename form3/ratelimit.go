@@ -0,0 +1,208 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrRateLimitExceedsDeadline is returned when waiting for the rate limiter would exceed the context deadline.
+var ErrRateLimitExceedsDeadline = errors.New("rate limiter wait would exceed context deadline")
+
+// Rate limit response headers
+const (
+	rateLimitRemainingHeader = "X-RateLimit-Remaining"
+	rateLimitResetHeader     = "X-RateLimit-Reset"
+)
+
+// RateLimiter is a client-side token-bucket rate limiter with separate limits for reads and writes.
+// It also adapts to the X-RateLimit-* and Retry-After headers sent by the Form3 API.
+// It is safe for concurrent use and can be shared between clients.
+type RateLimiter struct {
+	mu sync.Mutex
+
+	read  *tokenBucket
+	write *tokenBucket
+
+	// blockedUntil is the time until which the server asked us to stop sending requests.
+	blockedUntil time.Time
+
+	// now returns the current time. It is replaced in tests.
+	now func() time.Time
+}
+
+// NewRateLimiter returns a rate limiter allowing rps requests per second, with bursts of up to burst requests,
+// shared between reads and writes.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	bucket := newTokenBucket(rps, burst)
+	return &RateLimiter{read: bucket, write: bucket, now: time.Now}
+}
+
+// NewReadWriteRateLimiter returns a rate limiter with separate limits for reads (GET, HEAD and OPTIONS) and writes.
+// A rate lower or equal than zero disables the corresponding limit.
+func NewReadWriteRateLimiter(readRPS float64, readBurst int, writeRPS float64, writeBurst int) *RateLimiter {
+	return &RateLimiter{
+		read:  newTokenBucket(readRPS, readBurst),
+		write: newTokenBucket(writeRPS, writeBurst),
+		now:   time.Now,
+	}
+}
+
+// Wait blocks until a request with the given method is allowed to be sent and returns the time it waited.
+// It returns ErrRateLimitExceedsDeadline straight away if the wait would exceed the context deadline.
+func (rl *RateLimiter) Wait(ctx context.Context, method string) (time.Duration, error) {
+	if rl == nil {
+		return 0, nil
+	}
+
+	bucket := rl.write
+	if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+		bucket = rl.read
+	}
+
+	rl.mu.Lock()
+	now := rl.now()
+	delay := bucket.reserve(now)
+	if blocked := rl.blockedUntil.Sub(now); blocked > delay {
+		delay = blocked
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && deadline.Sub(now) < delay {
+		bucket.cancel(now)
+		rl.mu.Unlock()
+		return 0, ErrRateLimitExceedsDeadline
+	}
+	rl.mu.Unlock()
+
+	if delay <= 0 {
+		return 0, nil
+	}
+
+	if err := sleepContext(ctx, delay); err != nil {
+		rl.mu.Lock()
+		bucket.cancel(rl.now())
+		rl.mu.Unlock()
+		return 0, err
+	}
+
+	return delay, nil
+}
+
+// waitRateLimiter waits for the client rate limiter, if any, to allow the request and records how long it waited.
+func (c *Client) waitRateLimiter(ctx context.Context, r *Request) error {
+	if c.RateLimiter == nil {
+		return nil
+	}
+
+	wait, err := c.RateLimiter.Wait(ctx, r.Method)
+	if err != nil {
+		return err
+	}
+
+	c.metrics().ObserveRateLimitWait(metricsOperation(r), r.Method, wait)
+	return nil
+}
+
+// Update adapts the rate limiter to the rate limit information sent by the server in the given response.
+func (rl *RateLimiter) Update(res *http.Response) {
+	if rl == nil || res == nil {
+		return
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	until := time.Time{}
+
+	if remaining, err := strconv.Atoi(res.Header.Get(rateLimitRemainingHeader)); err == nil && remaining <= 0 {
+		if reset, ok := parseRateLimitReset(res.Header.Get(rateLimitResetHeader), now); ok {
+			until = reset
+		}
+	}
+
+	if res.StatusCode == http.StatusTooManyRequests {
+		if delay, ok := parseRetryAfter(res.Header.Get("Retry-After"), now); ok && now.Add(delay).After(until) {
+			until = now.Add(delay)
+		}
+	}
+
+	if until.After(rl.blockedUntil) {
+		rl.blockedUntil = until
+	}
+}
+
+// parseRateLimitReset parses the X-RateLimit-Reset header, which is either a Unix timestamp or a number of seconds.
+func parseRateLimitReset(value string, now time.Time) (time.Time, bool) {
+	reset, err := strconv.ParseFloat(value, 64)
+	if err != nil || reset < 0 {
+		return time.Time{}, false
+	}
+
+	// Values this large can only be Unix timestamps.
+	if reset > 1e9 {
+		sec, frac := math.Modf(reset)
+		return time.Unix(int64(sec), int64(frac*1e9)), true
+	}
+
+	return now.Add(time.Duration(reset * float64(time.Second))), true
+}
+
+// tokenBucket is a token bucket which allows reservations in advance, taking the number of tokens below zero.
+// A nil bucket does not limit anything. It must be protected by the caller.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns a full token bucket, or nil if the rate does not limit anything.
+func newTokenBucket(rps float64, burst int) *tokenBucket {
+	if rps <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rps, burst: float64(burst), tokens: float64(burst)}
+}
+
+// reserve takes a token and returns how long the caller must wait before using it.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+
+	b.advance(now)
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel gives back a token taken by reserve that will not be used.
+func (b *tokenBucket) cancel(now time.Time) {
+	if b == nil {
+		return
+	}
+
+	b.advance(now)
+	b.tokens = math.Min(b.tokens+1, b.burst)
+}
+
+// advance refills the bucket with the tokens generated since the last call.
+func (b *tokenBucket) advance(now time.Time) {
+	if !b.last.IsZero() && now.After(b.last) {
+		b.tokens = math.Min(b.tokens+now.Sub(b.last).Seconds()*b.rate, b.burst)
+	}
+	if now.After(b.last) {
+		b.last = now
+	}
+}
@@ -0,0 +1,209 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Wait(t *testing.T) {
+	tests := []struct {
+		name      string
+		limiter   *RateLimiter
+		methods   []string
+		wantDelay time.Duration
+	}{
+		{
+			name:      "Test requests within burst are not delayed",
+			limiter:   NewRateLimiter(1, 3),
+			methods:   []string{http.MethodGet, http.MethodPost, http.MethodGet},
+			wantDelay: 0,
+		},
+		{
+			name:      "Test requests over burst are delayed",
+			limiter:   NewRateLimiter(10, 2),
+			methods:   []string{http.MethodGet, http.MethodGet, http.MethodGet},
+			wantDelay: 100 * time.Millisecond,
+		},
+		{
+			name:      "Test reads and writes have separate limits",
+			limiter:   NewReadWriteRateLimiter(10, 1, 10, 1),
+			methods:   []string{http.MethodGet, http.MethodPost},
+			wantDelay: 0,
+		},
+		{
+			name:      "Test disabled write limit",
+			limiter:   NewReadWriteRateLimiter(10, 1, 0, 0),
+			methods:   []string{http.MethodPost, http.MethodDelete, http.MethodPost},
+			wantDelay: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			tt.limiter.now = func() time.Time { return now }
+
+			var delay time.Duration
+			for _, method := range tt.methods {
+				waited, err := tt.limiter.Wait(context.Background(), method)
+				if err != nil {
+					t.Fatalf("RateLimiter.Wait() error = %v", err)
+				}
+				delay += waited
+			}
+
+			if delay != tt.wantDelay {
+				t.Fatalf("RateLimiter.Wait() - delay - got = %v, want %v", delay, tt.wantDelay)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_WaitExceedsDeadline(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+
+	if _, err := limiter.Wait(context.Background(), http.MethodGet); err != nil {
+		t.Fatalf("RateLimiter.Wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := limiter.Wait(ctx, http.MethodGet)
+	if !errors.Is(err, ErrRateLimitExceedsDeadline) {
+		t.Fatalf("RateLimiter.Wait() error - got = %v, want %v", err, ErrRateLimitExceedsDeadline)
+	}
+
+	// The rejected request must give its token back.
+	if tokens := limiter.read.tokens; tokens < -0.01 {
+		t.Fatalf("RateLimiter.Wait() - tokens - got = %v, want %v", tokens, 0)
+	}
+}
+
+func TestRateLimiter_Update(t *testing.T) {
+	now := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		statusCode  int
+		headers     map[string]string
+		wantBlocked time.Duration
+	}{
+		{
+			name:        "Test remaining requests",
+			statusCode:  200,
+			headers:     map[string]string{"X-RateLimit-Remaining": "10", "X-RateLimit-Reset": "30"},
+			wantBlocked: 0,
+		},
+		{
+			name:        "Test no remaining requests with relative reset",
+			statusCode:  200,
+			headers:     map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "30"},
+			wantBlocked: 30 * time.Second,
+		},
+		{
+			name:        "Test no remaining requests with timestamp reset",
+			statusCode:  200,
+			headers:     map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": strconv.FormatInt(now.Add(time.Minute).Unix(), 10)},
+			wantBlocked: time.Minute,
+		},
+		{
+			name:        "Test rate limited with Retry-After",
+			statusCode:  429,
+			headers:     map[string]string{"Retry-After": "5"},
+			wantBlocked: 5 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewRateLimiter(0, 0)
+			limiter.now = func() time.Time { return now }
+
+			res := &http.Response{StatusCode: tt.statusCode, Header: http.Header{}}
+			for k, v := range tt.headers {
+				res.Header.Set(k, v)
+			}
+			limiter.Update(res)
+
+			blocked := limiter.blockedUntil.Sub(now)
+			if limiter.blockedUntil.IsZero() {
+				blocked = 0
+			}
+			if blocked != tt.wantBlocked {
+				t.Fatalf("RateLimiter.Update() - blocked - got = %v, want %v", blocked, tt.wantBlocked)
+			}
+		})
+	}
+}
+
+func TestClient_Do_RateLimiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "60")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"data": {"id": "10"}}`))
+	}))
+	defer server.Close()
+
+	serverUrl, _ := url.Parse(server.URL)
+	client := &Client{BaseURL: serverUrl, client: server.Client(), RateLimiter: NewRateLimiter(100, 10)}
+
+	var result map[string]interface{}
+	if err := client.Do(context.Background(), http.MethodGet, "/v1/accounts/10", nil, &result); err != nil {
+		t.Fatalf("Client.Do() error = %v", err)
+	}
+
+	// The server told us there are no requests left for a minute.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err := client.Do(ctx, http.MethodGet, "/v1/accounts/10", nil, &result)
+	if !errors.Is(err, ErrRateLimitExceedsDeadline) {
+		t.Fatalf("Client.Do() error - got = %v, want %v", err, ErrRateLimitExceedsDeadline)
+	}
+}
+
+// signerFunc is a Signer calling a function.
+type signerFunc func(req *http.Request, body []byte) error
+
+func (f signerFunc) Sign(req *http.Request, body []byte) error { return f(req, body) }
+
+func TestClient_Do_RateLimiterBeforeSigning(t *testing.T) {
+	var received []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, time.Now())
+		w.Write([]byte(`{"data": {"id": "10"}}`))
+	}))
+	defer server.Close()
+
+	var signed []time.Time
+	signer := signerFunc(func(req *http.Request, body []byte) error {
+		signed = append(signed, time.Now())
+		return nil
+	})
+
+	serverUrl, _ := url.Parse(server.URL)
+	client := &Client{BaseURL: serverUrl, client: server.Client(), RateLimiter: NewRateLimiter(5, 1), Signer: signer}
+
+	// The second request waits 200ms for the rate limiter.
+	var result map[string]interface{}
+	for i := 0; i < 2; i++ {
+		if err := client.Do(context.Background(), http.MethodGet, "/v1/accounts/10", nil, &result); err != nil {
+			t.Fatalf("Client.Do() error = %v", err)
+		}
+	}
+
+	if len(signed) != 2 || len(received) != 2 {
+		t.Fatalf("Client.Do() - requests - got = %d signed, %d received, want 2", len(signed), len(received))
+	}
+	if age := received[1].Sub(signed[1]); age > 100*time.Millisecond {
+		t.Fatalf("Client.Do() - signature age - got = %v, want the request signed after the rate limiter wait", age)
+	}
+}
@@ -0,0 +1,95 @@
+package form3
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Rate limit response headers
+const rateLimitLimitHeader = "X-RateLimit-Limit"
+
+// Response is the metadata of a response of the Form3 API.
+type Response struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Header contains the response headers.
+	Header http.Header
+
+	// RequestID is the ID Form3 assigned to the request, to be quoted in support tickets.
+	RequestID string
+
+	// Location is the URL of the created or moved resource, if any.
+	Location string
+
+	// ETag is the entity tag of the returned resource, if any.
+	ETag string
+
+	// RateLimit is the rate limit information sent by the Form3 API, if any.
+	RateLimit RateLimitInfo
+
+	// Links are the pagination links of the response body, if the decoded result has them.
+	Links *Form3BodyResponseLinks
+
+	// FromCache tells that the returned resource comes from the client cache. The other fields describe the
+	// revalidation response, if the cached resource was revalidated, and they are empty otherwise.
+	FromCache bool
+}
+
+// RateLimitInfo is the rate limit information sent in the X-RateLimit-* headers.
+type RateLimitInfo struct {
+	// Limit is the number of requests allowed in the current window, or -1 if unknown.
+	Limit int
+
+	// Remaining is the number of requests left in the current window, or -1 if unknown.
+	Remaining int
+
+	// Reset is when the current window ends, or the zero time if unknown.
+	Reset time.Time
+}
+
+// linksResponse is implemented by response bodies carrying pagination links.
+type linksResponse interface {
+	responseLinks() *Form3BodyResponseLinks
+}
+
+// responseLinks implements linksResponse.
+func (r *Form3BodyResponse[T]) responseLinks() *Form3BodyResponseLinks {
+	return &r.Links
+}
+
+// newResponse returns the metadata of the given HTTP response. Links are only set if the result has them.
+func newResponse(res *http.Response, result interface{}) *Response {
+	response := &Response{
+		StatusCode: res.StatusCode,
+		Header:     res.Header,
+		RequestID:  res.Header.Get(requestIDHeader),
+		Location:   res.Header.Get("Location"),
+		ETag:       res.Header.Get("ETag"),
+		RateLimit:  parseRateLimitInfo(res.Header, time.Now()),
+	}
+
+	if withLinks, ok := result.(linksResponse); ok {
+		response.Links = withLinks.responseLinks()
+	}
+
+	return response
+}
+
+// parseRateLimitInfo parses the X-RateLimit-* headers.
+func parseRateLimitInfo(header http.Header, now time.Time) RateLimitInfo {
+	info := RateLimitInfo{Limit: -1, Remaining: -1}
+
+	if limit, err := strconv.Atoi(header.Get(rateLimitLimitHeader)); err == nil {
+		info.Limit = limit
+	}
+	if remaining, err := strconv.Atoi(header.Get(rateLimitRemainingHeader)); err == nil {
+		info.Remaining = remaining
+	}
+	if reset, ok := parseRateLimitReset(header.Get(rateLimitResetHeader), now); ok {
+		info.Reset = reset
+	}
+
+	return info
+}
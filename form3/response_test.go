@@ -0,0 +1,101 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_DoWithResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.Header().Set("X-RateLimit-Limit", "1000")
+		w.Header().Set("X-RateLimit-Remaining", "998")
+		w.Header().Set("X-RateLimit-Reset", "1683000000")
+		w.Header().Set("ETag", `"v1"`)
+
+		if r.Method == http.MethodPost {
+			w.Header().Set("Location", "/v1/organisation/accounts/10")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"data": {"id": "10"}, "links": {"self": "/v1/organisation/accounts/10"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error_message": "record 10 does not exist"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithBaseURL(server.URL+"/v1/"), WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		call    func() (*Response, error)
+		wantErr bool
+		want    Response
+	}{
+		{
+			name: "Test metadata of a successful response",
+			call: func() (*Response, error) {
+				_, res, err := client.Account.Create(context.Background(), "10", "", &CreateAccountAttributes{BankID: "400300", BankIDCode: "GBDSC", Bic: "NWBKGB22", Country: "GB"})
+				return res, err
+			},
+			want: Response{
+				StatusCode: http.StatusCreated,
+				RequestID:  "req-123",
+				Location:   "/v1/organisation/accounts/10",
+				ETag:       `"v1"`,
+				RateLimit:  RateLimitInfo{Limit: 1000, Remaining: 998, Reset: time.Unix(1683000000, 0)},
+				Links:      &Form3BodyResponseLinks{Self: "/v1/organisation/accounts/10"},
+			},
+		},
+		{
+			name: "Test metadata of an error response",
+			call: func() (*Response, error) {
+				return client.DoWithResponse(context.Background(), http.MethodGet, "organisation/accounts/10", nil, nil)
+			},
+			wantErr: true,
+			want: Response{
+				StatusCode: http.StatusNotFound,
+				RequestID:  "req-123",
+				ETag:       `"v1"`,
+				RateLimit:  RateLimitInfo{Limit: 1000, Remaining: 998, Reset: time.Unix(1683000000, 0)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tt.call()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if res == nil {
+				t.Fatalf("response - got = %v, want %+v", res, tt.want)
+			}
+
+			if res.StatusCode != tt.want.StatusCode {
+				t.Fatalf("response - StatusCode - got = %v, want %v", res.StatusCode, tt.want.StatusCode)
+			}
+			if res.RequestID != tt.want.RequestID {
+				t.Fatalf("response - RequestID - got = %v, want %v", res.RequestID, tt.want.RequestID)
+			}
+			if res.Location != tt.want.Location {
+				t.Fatalf("response - Location - got = %v, want %v", res.Location, tt.want.Location)
+			}
+			if res.ETag != tt.want.ETag {
+				t.Fatalf("response - ETag - got = %v, want %v", res.ETag, tt.want.ETag)
+			}
+			if res.RateLimit.Limit != tt.want.RateLimit.Limit || res.RateLimit.Remaining != tt.want.RateLimit.Remaining || !res.RateLimit.Reset.Equal(tt.want.RateLimit.Reset) {
+				t.Fatalf("response - RateLimit - got = %+v, want %+v", res.RateLimit, tt.want.RateLimit)
+			}
+			if (res.Links == nil) != (tt.want.Links == nil) || res.Links != nil && *res.Links != *tt.want.Links {
+				t.Fatalf("response - Links - got = %+v, want %+v", res.Links, tt.want.Links)
+			}
+		})
+	}
+}
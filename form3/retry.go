@@ -0,0 +1,218 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Retry defaults
+const (
+	defaultRetryMaxAttempts = 3
+	defaultRetryBaseDelay   = 200 * time.Millisecond
+	defaultRetryMaxDelay    = 5 * time.Second
+	defaultRetryJitter      = 0.5
+)
+
+// RetryPolicy configures how Client.Do retries requests that failed with a transient error.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one. Values lower than 2 disable retries.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. It is doubled on every following retry.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay computed by the exponential backoff. It does not cap Retry-After.
+	MaxDelay time.Duration
+
+	// Jitter is the fraction (between 0 and 1) of every delay that is randomised.
+	Jitter float64
+
+	// RetryableStatusCodes lists the response status codes that trigger a retry.
+	RetryableStatusCodes []int
+
+	// IsRetryableError reports whether a transport error triggers a retry.
+	// If nil, IsRetryableNetworkError is used.
+	IsRetryableError func(err error) bool
+
+	// RetryNonIdempotent allows retrying POST and PATCH requests even when the server might have processed them.
+	// By default they are only retried when the request never reached the server or it was rate limited.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a retry policy suitable for most Form3 API consumers.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: defaultRetryMaxAttempts,
+		BaseDelay:   defaultRetryBaseDelay,
+		MaxDelay:    defaultRetryMaxDelay,
+		Jitter:      defaultRetryJitter,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// IsRetryableNetworkError reports whether the given transport error is transient.
+// Cancelled requests are never retryable, while timeouts are.
+func IsRetryableNetworkError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// shouldRetry reports whether the given attempt, which ended with either a response or a transport error,
+// has to be retried and how long to wait before doing it.
+func (c *Client) shouldRetry(ctx context.Context, req *http.Request, res *http.Response, err error, attempt int) (bool, time.Duration) {
+	p := c.RetryPolicy
+	if attempt >= p.maxAttempts() || ctx.Err() != nil {
+		return false, 0
+	}
+
+	if err != nil && !p.shouldRetryError(req, err) || err == nil && !p.shouldRetryResponse(req, res) {
+		return false, 0
+	}
+
+	// There is no point in waiting if the context will expire before the next attempt.
+	delay := p.backoff(attempt, res)
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return false, 0
+	}
+
+	return true, delay
+}
+
+// maxAttempts returns the number of attempts allowed by the policy.
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// shouldRetryError reports whether a request that failed with the given transport error can be sent again.
+func (p *RetryPolicy) shouldRetryError(req *http.Request, err error) bool {
+	isRetryable := p.IsRetryableError
+	if isRetryable == nil {
+		isRetryable = IsRetryableNetworkError
+	}
+	if !isRetryable(err) {
+		return false
+	}
+
+	// A request that never left the client can always be sent again.
+	return isIdempotent(req) || p.RetryNonIdempotent || isDialError(err)
+}
+
+// shouldRetryResponse reports whether a request answered with the given response can be sent again.
+func (p *RetryPolicy) shouldRetryResponse(req *http.Request, res *http.Response) bool {
+	retryable := false
+	for _, code := range p.RetryableStatusCodes {
+		if res.StatusCode == code {
+			retryable = true
+			break
+		}
+	}
+	if !retryable {
+		return false
+	}
+
+	// A rate limited request has not been processed, so it is safe to send again.
+	return isIdempotent(req) || p.RetryNonIdempotent || res.StatusCode == http.StatusTooManyRequests
+}
+
+// backoff returns the delay to wait before the given retry (starting at 1), honouring the Retry-After header if any.
+func (p *RetryPolicy) backoff(retry int, res *http.Response) time.Duration {
+	if res != nil {
+		if delay, ok := parseRetryAfter(res.Header.Get("Retry-After"), time.Now()); ok {
+			return delay
+		}
+	}
+
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(retry-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	jitter := math.Min(math.Max(p.Jitter, 0), 1)
+	delay = delay*(1-jitter) + rand.Float64()*delay*jitter
+
+	return time.Duration(delay)
+}
+
+// parseRetryAfter parses a Retry-After header value, which is either a number of seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if delay := date.Sub(now); delay > 0 {
+		return delay, true
+	}
+	return 0, true
+}
+
+// isIdempotent reports whether the request can be safely repeated, because of its method or its idempotency key.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	// The server does not process twice requests with the same idempotency key.
+	return req.Header.Get(idempotencyKeyHeader) != ""
+}
+
+// isDialError reports whether the error happened while establishing the connection, before sending anything.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// sleepContext waits for the given duration or until the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// drainBody discards the rest of the response body and closes it so the connection can be reused.
+func drainBody(res *http.Response) {
+	io.Copy(io.Discard, io.LimitReader(res.Body, 4096))
+	res.Body.Close()
+}
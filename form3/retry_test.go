@@ -0,0 +1,227 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_Do_Retry(t *testing.T) {
+	testCases := []struct {
+		name          string
+		method        string
+		body          interface{}
+		statusCodes   []int
+		retryAfter    string
+		policy        *RetryPolicy
+		wantAttempts  int32
+		wantErrStatus int
+	}{
+		{
+			name:         "Test GET is retried until success",
+			method:       http.MethodGet,
+			statusCodes:  []int{503, 502, 200},
+			policy:       testRetryPolicy(3),
+			wantAttempts: 3,
+		},
+		{
+			name:          "Test GET stops after max attempts",
+			method:        http.MethodGet,
+			statusCodes:   []int{500, 500, 500, 500},
+			policy:        testRetryPolicy(3),
+			wantAttempts:  3,
+			wantErrStatus: 500,
+		},
+		{
+			name:          "Test GET is not retried on non retryable status",
+			method:        http.MethodGet,
+			statusCodes:   []int{404, 200},
+			policy:        testRetryPolicy(3),
+			wantAttempts:  1,
+			wantErrStatus: 404,
+		},
+		{
+			name:          "Test GET is not retried without policy",
+			method:        http.MethodGet,
+			statusCodes:   []int{503, 200},
+			policy:        nil,
+			wantAttempts:  1,
+			wantErrStatus: 503,
+		},
+		{
+			name:          "Test POST is not retried on server error",
+			method:        http.MethodPost,
+			body:          map[string]string{"id": "10"},
+			statusCodes:   []int{500, 200},
+			policy:        testRetryPolicy(3),
+			wantAttempts:  1,
+			wantErrStatus: 500,
+		},
+		{
+			name:         "Test POST is retried when rate limited",
+			method:       http.MethodPost,
+			body:         map[string]string{"id": "10"},
+			statusCodes:  []int{429, 200},
+			retryAfter:   "0",
+			policy:       testRetryPolicy(3),
+			wantAttempts: 2,
+		},
+		{
+			name:         "Test POST is retried on server error when non idempotent retries are allowed",
+			method:       http.MethodPost,
+			body:         map[string]string{"id": "10"},
+			statusCodes:  []int{500, 200},
+			policy:       &RetryPolicy{MaxAttempts: 2, RetryableStatusCodes: []int{500}, RetryNonIdempotent: true},
+			wantAttempts: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempt := atomic.AddInt32(&attempts, 1)
+
+				// Every attempt must carry the full body.
+				if tc.body != nil {
+					body, _ := io.ReadAll(r.Body)
+					if string(body) != `{"id":"10"}` {
+						t.Errorf("attempt %d - body - got = %s, want %s", attempt, body, `{"id":"10"}`)
+					}
+				}
+
+				if tc.retryAfter != "" {
+					w.Header().Set("Retry-After", tc.retryAfter)
+				}
+				w.WriteHeader(tc.statusCodes[attempt-1])
+				w.Write([]byte(`{"data": {"id": "10"}}`))
+			}))
+			defer server.Close()
+
+			serverUrl, _ := url.Parse(server.URL)
+			client := &Client{BaseURL: serverUrl, client: server.Client(), RetryPolicy: tc.policy}
+
+			var result map[string]interface{}
+			err := client.Do(context.Background(), tc.method, "/v1/accounts", tc.body, &result)
+
+			if got := atomic.LoadInt32(&attempts); got != tc.wantAttempts {
+				t.Fatalf("Client.Do() - attempts - got = %v, want %v", got, tc.wantAttempts)
+			}
+
+			if tc.wantErrStatus == 0 {
+				if err != nil {
+					t.Fatalf("Client.Do() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var form3Error *Form3APIError
+			if !errors.As(err, &form3Error) {
+				t.Fatalf("Client.Do() - result type - got = %T, want %T", err, &Form3APIError{})
+			}
+			if form3Error.StatusCode != tc.wantErrStatus {
+				t.Fatalf("Client.Do() - status code - got = %v, want %v", form3Error.StatusCode, tc.wantErrStatus)
+			}
+		})
+	}
+}
+
+func TestClient_Do_RetryStopsOnContextCancel(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	serverUrl, _ := url.Parse(server.URL)
+	policy := testRetryPolicy(10)
+	policy.BaseDelay = 50 * time.Millisecond
+	policy.MaxDelay = 50 * time.Millisecond
+	client := &Client{BaseURL: serverUrl, client: server.Client(), RetryPolicy: policy}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(75*time.Millisecond, cancel)
+
+	err := client.Do(ctx, http.MethodGet, "/v1/accounts", nil, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Client.Do() error - got = %v, want %v", err, context.Canceled)
+	}
+
+	if got := atomic.LoadInt32(&attempts); got >= 10 {
+		t.Fatalf("Client.Do() - attempts - got = %v, want less than %v", got, 10)
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := &RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, Jitter: 0.5}
+
+	tests := []struct {
+		name    string
+		retry   int
+		header  string
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{name: "Test first retry", retry: 1, wantMin: 50 * time.Millisecond, wantMax: 100 * time.Millisecond},
+		{name: "Test third retry", retry: 3, wantMin: 200 * time.Millisecond, wantMax: 400 * time.Millisecond},
+		{name: "Test delay is capped", retry: 10, wantMin: 500 * time.Millisecond, wantMax: time.Second},
+		{name: "Test Retry-After in seconds", retry: 1, header: "3", wantMin: 3 * time.Second, wantMax: 3 * time.Second},
+		{name: "Test invalid Retry-After", retry: 1, header: "soon", wantMin: 50 * time.Millisecond, wantMax: 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &http.Response{Header: http.Header{}}
+			if tt.header != "" {
+				res.Header.Set("Retry-After", tt.header)
+			}
+
+			got := policy.backoff(tt.retry, res)
+			if got < tt.wantMin || got > tt.wantMax {
+				t.Fatalf("RetryPolicy.backoff() - got = %v, want between %v and %v", got, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOk bool
+	}{
+		{name: "Test empty value", value: "", want: 0, wantOk: false},
+		{name: "Test seconds", value: "120", want: 2 * time.Minute, wantOk: true},
+		{name: "Test negative seconds", value: "-1", want: 0, wantOk: false},
+		{name: "Test HTTP date", value: "Mon, 01 May 2023 10:00:30 GMT", want: 30 * time.Second, wantOk: true},
+		{name: "Test HTTP date in the past", value: "Mon, 01 May 2023 09:00:00 GMT", want: 0, wantOk: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value, now)
+			if got != tt.want || ok != tt.wantOk {
+				t.Fatalf("parseRetryAfter() - got = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+// testRetryPolicy returns the default retry policy with the given attempts and no delays.
+func testRetryPolicy(maxAttempts int) *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = maxAttempts
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = time.Millisecond
+	return policy
+}
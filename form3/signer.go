@@ -0,0 +1,164 @@
+package form3
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Signature pseudo headers and algorithms
+const (
+	SignatureRequestTarget = "(request-target)"
+
+	signatureAlgorithmRSA   = "rsa-sha256"
+	signatureAlgorithmECDSA = "ecdsa-sha256"
+)
+
+// DefaultSignatureHeaders is the list of headers signed when none is given to NewSigner.
+var DefaultSignatureHeaders = []string{SignatureRequestTarget, "host", "date", "digest", "content-type"}
+
+// Signer signs the requests sent to the Form3 API. body is the already marshalled request body, if any.
+type Signer interface {
+	Sign(req *http.Request, body []byte) error
+}
+
+// HTTPSigner signs requests with the HTTP Signatures scheme required by the Form3 API.
+// It sets the Date and Digest headers and the Authorization header with the signature.
+type HTTPSigner struct {
+	keyID     string
+	key       crypto.Signer
+	algorithm string
+	headers   []string
+
+	// now returns the current time. It is replaced in tests.
+	now func() time.Time
+}
+
+// NewSigner returns a signer using the given RSA or ECDSA private key, identified in Form3 by keyID.
+// headers is the list of headers to sign, DefaultSignatureHeaders if empty. Headers missing from a request,
+// such as content-type on requests without body, are not signed.
+func NewSigner(keyID string, privateKey crypto.Signer, headers ...string) (*HTTPSigner, error) {
+	if keyID == "" {
+		return nil, errors.New("invalid key ID: it must not be empty")
+	}
+
+	var algorithm string
+	switch privateKey.(type) {
+	case *rsa.PrivateKey:
+		algorithm = signatureAlgorithmRSA
+	case *ecdsa.PrivateKey:
+		algorithm = signatureAlgorithmECDSA
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T: it must be RSA or ECDSA", privateKey)
+	}
+
+	if len(headers) == 0 {
+		headers = DefaultSignatureHeaders
+	}
+	lowerHeaders := make([]string, len(headers))
+	for i, header := range headers {
+		lowerHeaders[i] = strings.ToLower(header)
+	}
+
+	return &HTTPSigner{keyID: keyID, key: privateKey, algorithm: algorithm, headers: lowerHeaders, now: time.Now}, nil
+}
+
+// NewSignerFromPEM returns a signer using the PEM encoded RSA or ECDSA private key.
+// PKCS #1, PKCS #8 and SEC 1 keys are supported.
+func NewSignerFromPEM(keyID string, pemKey []byte, headers ...string) (*HTTPSigner, error) {
+	block, _ := pem.Decode(pemKey)
+	if block == nil {
+		return nil, errors.New("failed to decode PEM private key")
+	}
+
+	var (
+		key interface{}
+		err error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+
+	return NewSigner(keyID, signer, headers...)
+}
+
+// Sign adds the Date, Digest and Authorization headers to the request.
+func (s *HTTPSigner) Sign(req *http.Request, body []byte) error {
+	if req.Header.Get("Date") == "" {
+		req.Header.Set("Date", s.now().UTC().Format(http.TimeFormat))
+	}
+
+	bodyDigest := sha256.Sum256(body)
+	req.Header.Set("Digest", "SHA-256="+base64.StdEncoding.EncodeToString(bodyDigest[:]))
+
+	signedHeaders, signingString := signingString(req, s.headers)
+
+	digest := sha256.Sum256([]byte(signingString))
+	signature, err := s.key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf(`Signature keyId="%s",algorithm="%s",headers="%s",signature="%s"`,
+		s.keyID, s.algorithm, strings.Join(signedHeaders, " "), base64.StdEncoding.EncodeToString(signature)))
+
+	return nil
+}
+
+// signingString builds the string to sign from the given headers of the request.
+// It returns the headers actually included, skipping the ones missing from the request.
+func signingString(req *http.Request, headers []string) ([]string, string) {
+	var (
+		signedHeaders []string
+		lines         []string
+	)
+
+	for _, header := range headers {
+		var value string
+		switch header {
+		case SignatureRequestTarget:
+			value = strings.ToLower(req.Method) + " " + req.URL.RequestURI()
+		case "host":
+			value = req.Host
+			if value == "" {
+				value = req.URL.Host
+			}
+		default:
+			value = strings.Join(req.Header.Values(header), ", ")
+		}
+
+		if value == "" {
+			continue
+		}
+
+		signedHeaders = append(signedHeaders, header)
+		lines = append(lines, header+": "+value)
+	}
+
+	return signedHeaders, strings.Join(lines, "\n")
+}
@@ -0,0 +1,210 @@
+package form3
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestClient_Do_Signer(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaDER, err := x509.MarshalECPrivateKey(ecdsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8DER, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name          string
+		pemKey        []byte
+		publicKey     crypto.PublicKey
+		headers       []string
+		method        string
+		body          interface{}
+		wantAlgorithm string
+		wantHeaders   string
+	}{
+		{
+			name:          "Test RSA PKCS #1 key signing a POST",
+			pemKey:        pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}),
+			publicKey:     &rsaKey.PublicKey,
+			method:        http.MethodPost,
+			body:          map[string]string{"id": "10"},
+			wantAlgorithm: "rsa-sha256",
+			wantHeaders:   "(request-target) host date digest content-type",
+		},
+		{
+			name:          "Test RSA PKCS #8 key signing a GET without content type",
+			pemKey:        pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8DER}),
+			publicKey:     &rsaKey.PublicKey,
+			method:        http.MethodGet,
+			wantAlgorithm: "rsa-sha256",
+			wantHeaders:   "(request-target) host date digest",
+		},
+		{
+			name:          "Test ECDSA key with custom headers",
+			pemKey:        pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecdsaDER}),
+			publicKey:     &ecdsaKey.PublicKey,
+			headers:       []string{"(request-target)", "Date", "Digest"},
+			method:        http.MethodDelete,
+			wantAlgorithm: "ecdsa-sha256",
+			wantHeaders:   "(request-target) date digest",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				algorithm, headers, err := verifySignature(r, tc.publicKey)
+				if err != nil {
+					w.WriteHeader(http.StatusUnauthorized)
+					w.Write([]byte(fmt.Sprintf(`{"error_message": %q}`, err.Error())))
+					return
+				}
+
+				if algorithm != tc.wantAlgorithm {
+					t.Errorf("algorithm - got = %v, want %v", algorithm, tc.wantAlgorithm)
+				}
+				if headers != tc.wantHeaders {
+					t.Errorf("headers - got = %v, want %v", headers, tc.wantHeaders)
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			signer, err := NewSignerFromPEM("75a8ba12-fff2-4a52-ad8a-e8b34c5ccec8", tc.pemKey, tc.headers...)
+			if err != nil {
+				t.Fatalf("NewSignerFromPEM() error = %v", err)
+			}
+
+			client, err := NewClient(WithBaseURL(server.URL+"/v1/"), WithHTTPClient(server.Client()), WithSigner(signer))
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			if err := client.Do(context.Background(), tc.method, "organisation/accounts?page[size]=10", tc.body, nil); err != nil {
+				t.Fatalf("Client.Do() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestNewSignerFromPEM(t *testing.T) {
+	tests := []struct {
+		name    string
+		keyID   string
+		pemKey  []byte
+		wantErr string
+	}{
+		{
+			name:    "Test invalid PEM",
+			keyID:   "key",
+			pemKey:  []byte("not a key"),
+			wantErr: "failed to decode PEM private key",
+		},
+		{
+			name:    "Test unsupported block type",
+			keyID:   "key",
+			pemKey:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte{1}}),
+			wantErr: "unsupported PEM block type",
+		},
+		{
+			name:    "Test invalid key bytes",
+			keyID:   "key",
+			pemKey:  pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: []byte{1}}),
+			wantErr: "failed to parse private key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSignerFromPEM(tt.keyID, tt.pemKey)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("NewSignerFromPEM() error - got = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+var signatureParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// verifySignature checks the Digest and the Authorization signature of the request as the Form3 API would.
+// It returns the algorithm and the signed headers.
+func verifySignature(r *http.Request, publicKey crypto.PublicKey) (string, string, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return "", "", err
+	}
+
+	bodyDigest := sha256.Sum256(body)
+	if want := "SHA-256=" + base64.StdEncoding.EncodeToString(bodyDigest[:]); r.Header.Get("Digest") != want {
+		return "", "", fmt.Errorf("invalid digest %q, want %q", r.Header.Get("Digest"), want)
+	}
+
+	authorization, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Signature ")
+	if !ok {
+		return "", "", fmt.Errorf("missing signature")
+	}
+
+	params := map[string]string{}
+	for _, match := range signatureParamRegexp.FindAllStringSubmatch(authorization, -1) {
+		params[match[1]] = match[2]
+	}
+
+	// The signing string is built from the request as received, following the HTTP Signatures draft.
+	var lines []string
+	for _, header := range strings.Split(params["headers"], " ") {
+		switch header {
+		case "(request-target)":
+			lines = append(lines, header+": "+strings.ToLower(r.Method)+" "+r.URL.RequestURI())
+		case "host":
+			lines = append(lines, header+": "+r.Host)
+		default:
+			lines = append(lines, header+": "+r.Header.Get(header))
+		}
+	}
+	digest := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+
+	signature, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil {
+		return "", "", err
+	}
+
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		err = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature)
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest[:], signature) {
+			err = fmt.Errorf("invalid ECDSA signature")
+		}
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	return params["algorithm"], params["headers"], nil
+}
@@ -0,0 +1,109 @@
+package form3
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
+)
+
+// ErrTimeout is matched by errors.Is on every TimeoutError.
+var ErrTimeout = errors.New("form3: request timed out")
+
+// TimeoutPhase is the phase of a request during which it timed out.
+type TimeoutPhase string
+
+// Request phases
+const (
+	// TimeoutPhaseDial is the phase in which the connection to the server is established.
+	TimeoutPhaseDial TimeoutPhase = "dial"
+
+	// TimeoutPhaseTLS is the TLS handshake.
+	TimeoutPhaseTLS TimeoutPhase = "tls"
+
+	// TimeoutPhaseHeaders is the phase in which the request is sent and the response headers are awaited.
+	TimeoutPhaseHeaders TimeoutPhase = "headers"
+
+	// TimeoutPhaseBody is the phase in which the response body is read.
+	TimeoutPhaseBody TimeoutPhase = "body"
+)
+
+// TimeoutError is returned when a request times out on the client side, for example because of the timeout set
+// with WithTimeout. Requests whose context is cancelled or expires return context.Canceled or
+// context.DeadlineExceeded instead.
+type TimeoutError struct {
+	// Phase is the phase of the request during which it timed out.
+	Phase TimeoutPhase
+
+	// Err is the underlying transport error.
+	Err error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("request timed out during %s: %v", e.Phase, e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrTimeout.
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+// Timeout always returns true, so the error is reported as a timeout by os.IsTimeout.
+func (e *TimeoutError) Timeout() bool {
+	return true
+}
+
+// phaseTracker follows the phases of an HTTP request with an httptrace.ClientTrace.
+type phaseTracker struct {
+	phase atomic.Value
+}
+
+// newPhaseTracker returns a tracker in the dial phase and the request with the tracing hooks attached.
+func newPhaseTracker(req *http.Request) (*phaseTracker, *http.Request) {
+	t := &phaseTracker{}
+	t.phase.Store(TimeoutPhaseDial)
+
+	trace := &httptrace.ClientTrace{
+		TLSHandshakeStart: func() { t.phase.Store(TimeoutPhaseTLS) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { t.phase.Store(TimeoutPhaseHeaders) },
+		GotConn:           func(httptrace.GotConnInfo) { t.phase.Store(TimeoutPhaseHeaders) },
+	}
+
+	return t, req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
+
+// current returns the current phase of the request.
+func (t *phaseTracker) current() TimeoutPhase {
+	return t.phase.Load().(TimeoutPhase)
+}
+
+// timeoutError wraps the transport error in a TimeoutError if the request timed out on the client side.
+// Errors caused by the context are returned as is.
+func (t *phaseTracker) timeoutError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+
+	var netErr interface{ Timeout() bool }
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return &TimeoutError{Phase: t.current(), Err: err}
+	}
+
+	return err
+}
+
+// contextError makes sure errors caused by the cancellation or expiration of the context match
+// context.Canceled or context.DeadlineExceeded.
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil && !errors.Is(err, ctxErr) {
+		return fmt.Errorf("%w: %w", ctxErr, err)
+	}
+	return err
+}
@@ -0,0 +1,160 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestClient_Do_TimeoutError(t *testing.T) {
+	// The server accepts connections but never answers, so TLS handshakes hang.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/body" {
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+		}
+		time.Sleep(500 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	blockingDial := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	tests := []struct {
+		name      string
+		baseURL   string
+		path      string
+		transport http.RoundTripper
+		wantPhase TimeoutPhase
+	}{
+		{
+			name:      "Test timeout while dialing",
+			baseURL:   server.URL,
+			path:      "dial",
+			transport: blockingDial,
+			wantPhase: TimeoutPhaseDial,
+		},
+		{
+			name:      "Test timeout during the TLS handshake",
+			baseURL:   "https://" + listener.Addr().String(),
+			path:      "tls",
+			transport: http.DefaultTransport,
+			wantPhase: TimeoutPhaseTLS,
+		},
+		{
+			name:      "Test timeout while awaiting headers",
+			baseURL:   server.URL,
+			path:      "headers",
+			transport: http.DefaultTransport,
+			wantPhase: TimeoutPhaseHeaders,
+		},
+		{
+			name:      "Test timeout while reading the body",
+			baseURL:   server.URL,
+			path:      "body",
+			transport: http.DefaultTransport,
+			wantPhase: TimeoutPhaseBody,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseURL, _ := url.Parse(tt.baseURL + "/")
+			client := &Client{BaseURL: baseURL, client: &http.Client{Transport: tt.transport, Timeout: 100 * time.Millisecond}}
+
+			var result map[string]interface{}
+			err := client.Do(context.Background(), http.MethodGet, tt.path, nil, &result)
+			if !errors.Is(err, ErrTimeout) {
+				t.Fatalf("Client.Do() error - got = %v, want %v", err, ErrTimeout)
+			}
+
+			var timeoutErr *TimeoutError
+			if !errors.As(err, &timeoutErr) {
+				t.Fatalf("Client.Do() - error type - got = %T, want %T", err, timeoutErr)
+			}
+			if timeoutErr.Phase != tt.wantPhase {
+				t.Fatalf("Client.Do() - Phase - got = %v, want %v", timeoutErr.Phase, tt.wantPhase)
+			}
+			if timeoutErr.Err == nil {
+				t.Fatalf("Client.Do() - Err - got = %v, want the transport error", timeoutErr.Err)
+			}
+
+			var apiErr *Form3APIError
+			if errors.As(err, &apiErr) {
+				t.Fatalf("Client.Do() - error type - got = %T, want no %T", err, apiErr)
+			}
+		})
+	}
+}
+
+func TestClient_Do_ContextError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	baseURL, _ := url.Parse(server.URL + "/")
+	client := &Client{BaseURL: baseURL, client: &http.Client{}}
+
+	tests := []struct {
+		name    string
+		ctx     func() (context.Context, context.CancelFunc)
+		wantErr error
+	}{
+		{
+			name: "Test expired context",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 100*time.Millisecond)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name: "Test cancelled context",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(100*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			wantErr: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			err := client.Do(ctx, http.MethodGet, "accounts/10", nil, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Client.Do() error - got = %v, want %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrTimeout) {
+				t.Fatalf("Client.Do() error - got = %v, want no %v", err, ErrTimeout)
+			}
+		})
+	}
+}
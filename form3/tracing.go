@@ -0,0 +1,149 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the OpenTelemetry tracer used by the client.
+const tracerName = "github.com/agatticelli/form3-client-go/form3"
+
+// Form3 specific span attributes
+const (
+	form3OperationKey    = attribute.Key("form3.operation")
+	form3ErrorMessageKey = attribute.Key("form3.error_message")
+)
+
+// tracer returns the tracer of the configured provider, or of the global one if none is configured.
+func (c *Client) tracer() trace.Tracer {
+	provider := c.TracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return provider.Tracer(tracerName)
+}
+
+// propagator returns the configured propagator, or the global one if none is configured.
+func (c *Client) propagator() propagation.TextMapPropagator {
+	if c.Propagator != nil {
+		return c.Propagator
+	}
+	return otel.GetTextMapPropagator()
+}
+
+// startOperationSpan starts the span covering a whole logical request, named after its operation.
+func (c *Client) startOperationSpan(ctx context.Context, r *Request) (context.Context, trace.Span) {
+	name := "form3.request"
+	if r.Operation != "" {
+		name = "form3." + r.Operation
+	}
+
+	return c.tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(
+			form3OperationKey.String(r.Operation),
+			semconv.HTTPRequestMethodKey.String(r.Method),
+			semconv.URLTemplate(r.urlTemplate()),
+		),
+	)
+}
+
+// endOperationSpan records the outcome of a logical request and ends its span.
+func endOperationSpan(span trace.Span, r *Request, res *Response, err error) {
+	if res != nil {
+		span.SetAttributes(semconv.HTTPResponseStatusCode(res.StatusCode))
+	}
+	if err != nil {
+		var apiErr *Form3APIError
+		if errors.As(err, &apiErr) {
+			span.SetAttributes(
+				semconv.HTTPResponseStatusCode(apiErr.StatusCode),
+				form3ErrorMessageKey.String(apiErr.Message),
+			)
+		}
+		recordError(span, err, r.urlTemplate())
+	}
+	span.End()
+}
+
+// startAttemptSpan starts the span covering a single HTTP attempt and propagates its context in the request headers.
+func (c *Client) startAttemptSpan(ctx context.Context, r *Request, req *http.Request, attempt int) trace.Span {
+	template := r.urlTemplate()
+
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.URLTemplate(template),
+		semconv.ServerAddress(req.URL.Hostname()),
+	}
+	if attempt > 1 {
+		attrs = append(attrs, semconv.HTTPRequestResendCount(attempt-1))
+	}
+
+	ctx, span := c.tracer().Start(ctx, req.Method+" "+template,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	c.propagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	return span
+}
+
+// endAttemptSpan records the outcome of a single HTTP attempt and ends its span.
+func endAttemptSpan(span trace.Span, r *Request, res *http.Response, err error) {
+	if err != nil {
+		span.SetAttributes(semconv.ErrorTypeKey.String(errorType(err)))
+		recordError(span, err, r.urlTemplate())
+	} else {
+		span.SetAttributes(semconv.HTTPResponseStatusCode(res.StatusCode))
+		if res.StatusCode >= 400 {
+			span.SetStatus(codes.Error, http.StatusText(res.StatusCode))
+		}
+	}
+	span.End()
+}
+
+// recordError records the error in the span like span.RecordError does, but with the URL quoted by transport errors
+// replaced by the URL template, as the URL contains resource IDs and query filters with personal data.
+func recordError(span trace.Span, err error, template string) {
+	message := redactErrorURL(err, template)
+	span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(
+		semconv.ExceptionType(fmt.Sprintf("%T", err)),
+		semconv.ExceptionMessage(message),
+	))
+	span.SetStatus(codes.Error, message)
+}
+
+// errorType returns a low cardinality description of a transport error.
+func errorType(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded) || os.IsTimeout(err):
+		return "timeout"
+	case isDialError(err):
+		return "dial"
+	}
+	return "_OTHER"
+}
+
+// urlTemplate returns the URL template of the request, which does not contain resource IDs.
+// Requests sent with Client.Do have no template, so their path without query is used.
+func (r *Request) urlTemplate() string {
+	if r.Template != "" {
+		return r.Template
+	}
+
+	path, _, _ := strings.Cut(r.URL, "?")
+	return path
+}
@@ -0,0 +1,179 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestClient_Do_Tracing(t *testing.T) {
+	var (
+		attempts     int32
+		traceparents []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error_message": "record does not exist"}`))
+	}))
+	defer server.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	client, err := NewClient(
+		WithBaseURL(server.URL+"/v1/"),
+		WithHTTPClient(server.Client()),
+		WithRetryPolicy(testRetryPolicy(2)),
+		WithTracerProvider(provider),
+		WithPropagator(propagation.TraceContext{}),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	client.Account.Fetch(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc")
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("spans - got = %v, want %v", len(spans), 3)
+	}
+
+	// Child spans end before their parent.
+	operation := spans[2]
+	tests := []struct {
+		name       string
+		span       tracetest.SpanStub
+		wantName   string
+		wantKind   trace.SpanKind
+		wantAttrs  map[attribute.Key]attribute.Value
+		wantStatus codes.Code
+	}{
+		{
+			name:     "Test operation span",
+			span:     operation,
+			wantName: "form3.accounts.fetch",
+			wantKind: trace.SpanKindInternal,
+			wantAttrs: map[attribute.Key]attribute.Value{
+				"form3.operation":           attribute.StringValue("accounts.fetch"),
+				"http.request.method":       attribute.StringValue("GET"),
+				"url.template":              attribute.StringValue("organisation/accounts/{id}"),
+				"http.response.status_code": attribute.IntValue(404),
+				"form3.error_message":       attribute.StringValue("record does not exist"),
+			},
+			wantStatus: codes.Error,
+		},
+		{
+			name:     "Test first attempt span",
+			span:     spans[0],
+			wantName: "GET organisation/accounts/{id}",
+			wantKind: trace.SpanKindClient,
+			wantAttrs: map[attribute.Key]attribute.Value{
+				"http.request.method":       attribute.StringValue("GET"),
+				"url.template":              attribute.StringValue("organisation/accounts/{id}"),
+				"http.response.status_code": attribute.IntValue(503),
+			},
+			wantStatus: codes.Error,
+		},
+		{
+			name:     "Test retried attempt span",
+			span:     spans[1],
+			wantName: "GET organisation/accounts/{id}",
+			wantKind: trace.SpanKindClient,
+			wantAttrs: map[attribute.Key]attribute.Value{
+				"http.request.resend_count": attribute.IntValue(1),
+				"http.response.status_code": attribute.IntValue(404),
+			},
+			wantStatus: codes.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.span.Name != tt.wantName {
+				t.Fatalf("span - name - got = %v, want %v", tt.span.Name, tt.wantName)
+			}
+			if tt.span.SpanKind != tt.wantKind {
+				t.Fatalf("span - kind - got = %v, want %v", tt.span.SpanKind, tt.wantKind)
+			}
+			if tt.span.Status.Code != tt.wantStatus {
+				t.Fatalf("span - status - got = %v, want %v", tt.span.Status.Code, tt.wantStatus)
+			}
+
+			attrs := map[attribute.Key]attribute.Value{}
+			for _, attr := range tt.span.Attributes {
+				attrs[attr.Key] = attr.Value
+			}
+			for key, want := range tt.wantAttrs {
+				if attrs[key] != want {
+					t.Fatalf("span - %s - got = %v, want %v", key, attrs[key].Emit(), want.Emit())
+				}
+			}
+
+			if tt.span.SpanContext.TraceID() != operation.SpanContext.TraceID() {
+				t.Fatalf("span - trace ID - got = %v, want %v", tt.span.SpanContext.TraceID(), operation.SpanContext.TraceID())
+			}
+		})
+	}
+
+	// Every attempt propagates its own span context.
+	for i, traceparent := range traceparents {
+		want := "00-" + spans[i].SpanContext.TraceID().String() + "-" + spans[i].SpanContext.SpanID().String() + "-01"
+		if traceparent != want {
+			t.Fatalf("attempt %d - traceparent - got = %v, want %v", i+1, traceparent, want)
+		}
+		if spans[i].Parent.SpanID() != operation.SpanContext.SpanID() {
+			t.Fatalf("attempt %d - parent - got = %v, want %v", i+1, spans[i].Parent.SpanID(), operation.SpanContext.SpanID())
+		}
+	}
+}
+
+func TestClient_Do_Tracing_TransportError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	client, err := NewClient(WithBaseURL(server.URL), WithTracerProvider(provider))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	client.Account.List(context.Background(), &ListAccountsOptions{Iban: "GB11NWBK40030041426819"})
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("spans - got = %v, want %v", len(spans), 2)
+	}
+
+	for _, span := range spans {
+		if span.Status.Code != codes.Error || !strings.Contains(span.Status.Description, "organisation/accounts") {
+			t.Fatalf("span %s - status - got = %v, want an error with the URL template", span.Name, span.Status)
+		}
+
+		recorded := span.Status.Description
+		for _, event := range span.Events {
+			for _, attr := range event.Attributes {
+				recorded += " " + attr.Value.Emit()
+			}
+		}
+		if len(span.Events) == 0 || strings.Contains(recorded, "GB11NWBK") || strings.Contains(recorded, "filter") {
+			t.Fatalf("span %s - error - got = %s, want the error without the query", span.Name, recorded)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package form3
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// ValidationError is a problem found in a field of a resource before sending it to the Form3 API.
+type ValidationError struct {
+	// Field is the JSON name of the field, such as "bank_id".
+	Field string
+
+	// Message describes the problem, such as "must be 6 digits".
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// ValidationErrors lists every problem found in a resource before sending it to the Form3 API.
+// It matches ErrValidation, like the validation errors returned by the Form3 API.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	problems := make([]string, len(e))
+	for i, err := range e {
+		problems[i] = err.Error()
+	}
+	return fmt.Sprintf("%s: %s", ErrValidation, strings.Join(problems, "; "))
+}
+
+// Is reports whether the target is ErrValidation.
+func (e ValidationErrors) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// presence tells whether a field must, can or cannot be set.
+type presence int
+
+const (
+	optional presence = iota
+	required
+	forbidden
+)
+
+// lengthRule constrains the presence and length of a field.
+type lengthRule struct {
+	presence presence
+	min, max int
+
+	// digits requires the field to be made of digits only, otherwise letters are allowed too.
+	digits bool
+}
+
+// countryRules are the account attributes rules of a country.
+// Ref: https://www.api-docs.form3.tech/api/schemes/fps-direct/accounts/accounts/account-attributes-per-country
+type countryRules struct {
+	bankID        lengthRule
+	bankIDCode    string
+	bic           presence
+	accountNumber lengthRule
+
+	// ibanLength is the length of the IBANs of the country, or zero if the country has no IBANs.
+	ibanLength int
+}
+
+// accountRules are the account attributes rules of every supported country.
+var accountRules = map[string]countryRules{
+	"AU": {bankID: lengthRule{optional, 6, 6, true}, bankIDCode: "AUBSB", bic: required, accountNumber: lengthRule{optional, 6, 10, true}},
+	"BE": {bankID: lengthRule{required, 3, 3, true}, bankIDCode: "BEBAC", accountNumber: lengthRule{optional, 7, 7, true}, ibanLength: 16},
+	"CA": {bankID: lengthRule{optional, 9, 9, true}, bankIDCode: "CACPA", bic: required, accountNumber: lengthRule{optional, 7, 12, true}},
+	"CH": {bankID: lengthRule{required, 5, 5, true}, bankIDCode: "CHBCC", accountNumber: lengthRule{optional, 12, 12, false}, ibanLength: 21},
+	"DE": {bankID: lengthRule{required, 8, 8, true}, bankIDCode: "DEBLZ", accountNumber: lengthRule{optional, 7, 7, true}, ibanLength: 22},
+	"ES": {bankID: lengthRule{required, 8, 8, true}, bankIDCode: "ESNCC", accountNumber: lengthRule{optional, 10, 10, true}, ibanLength: 24},
+	"FR": {bankID: lengthRule{required, 10, 10, false}, bankIDCode: "FR", accountNumber: lengthRule{optional, 10, 10, false}, ibanLength: 27},
+	"GB": {bankID: lengthRule{required, 6, 6, true}, bankIDCode: "GBDSC", bic: required, accountNumber: lengthRule{optional, 8, 8, true}, ibanLength: 22},
+	"GR": {bankID: lengthRule{required, 7, 7, true}, bankIDCode: "GRBIC", accountNumber: lengthRule{optional, 16, 16, true}, ibanLength: 27},
+	"HK": {bankID: lengthRule{optional, 3, 3, true}, bankIDCode: "HKNCC", bic: required, accountNumber: lengthRule{optional, 9, 12, true}},
+	"IT": {bankID: lengthRule{required, 10, 11, true}, bankIDCode: "ITNCC", accountNumber: lengthRule{optional, 12, 12, true}, ibanLength: 27},
+	"LU": {bankID: lengthRule{required, 3, 3, true}, bankIDCode: "LULUX", accountNumber: lengthRule{optional, 13, 13, false}, ibanLength: 20},
+	"NL": {bankID: lengthRule{presence: forbidden}, bic: required, accountNumber: lengthRule{optional, 10, 10, true}, ibanLength: 18},
+	"PL": {bankID: lengthRule{required, 8, 8, true}, bankIDCode: "PLKNR", accountNumber: lengthRule{optional, 16, 16, true}, ibanLength: 28},
+	"PT": {bankID: lengthRule{required, 8, 8, true}, bankIDCode: "PTNCC", accountNumber: lengthRule{optional, 11, 11, true}, ibanLength: 25},
+	"US": {bankID: lengthRule{required, 9, 9, true}, bankIDCode: "USABA", bic: required, accountNumber: lengthRule{optional, 6, 17, true}},
+}
+
+var (
+	countryPattern      = regexp.MustCompile(`^[A-Z]{2}$`)
+	bicPattern          = regexp.MustCompile(`^[A-Z]{6}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+	ibanPattern         = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]+$`)
+	digitsPattern       = regexp.MustCompile(`^[0-9]*$`)
+	alphanumericPattern = regexp.MustCompile(`^[A-Za-z0-9]*$`)
+)
+
+// Validate checks the attributes of an account to create against the rules of its country, so mistakes are found
+// without a round trip to the Form3 API. Countries without known rules only get their BIC and IBAN checked.
+// It returns ValidationErrors listing every problem found, or nil.
+func (a *CreateAccountAttributes) Validate() error {
+	if a == nil {
+		return ValidationErrors{{Field: "attributes", Message: "are required"}}
+	}
+
+	var errs ValidationErrors
+	add := func(field, format string, args ...interface{}) {
+		errs = append(errs, ValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch {
+	case a.Country == "":
+		add("country", "is required")
+	case !countryPattern.MatchString(a.Country):
+		add("country", "must be an ISO 3166-1 alpha-2 code")
+	}
+
+	if a.Bic != "" && !bicPattern.MatchString(a.Bic) {
+		add("bic", "must be 8 or 11 characters in the BIC format")
+	}
+
+	rules, known := accountRules[a.Country]
+	if known {
+		checkLength(add, "bank_id", a.BankID, rules.bankID, a.Country)
+
+		switch {
+		case rules.bankIDCode == "" && a.BankIDCode != "":
+			add("bank_id_code", "is not supported in %s", a.Country)
+		case rules.bankIDCode != "" && a.BankIDCode != rules.bankIDCode:
+			add("bank_id_code", "must be %s", rules.bankIDCode)
+		}
+
+		if rules.bic == required && a.Bic == "" {
+			add("bic", "is required in %s", a.Country)
+		}
+
+		checkLength(add, "account_number", valueOf(a.AccountNumber), rules.accountNumber, a.Country)
+	}
+
+	if a.Iban != nil {
+		switch {
+		case known && rules.ibanLength == 0:
+			add("iban", "is not supported in %s", a.Country)
+		case !ibanPattern.MatchString(*a.Iban):
+			add("iban", "must be a country code, 2 check digits and alphanumeric characters")
+		case !strings.HasPrefix(*a.Iban, a.Country):
+			add("iban", "must start with the country code %s", a.Country)
+		case known && len(*a.Iban) != rules.ibanLength:
+			add("iban", "must be %d characters in %s", rules.ibanLength, a.Country)
+		case !validIBANChecksum(*a.Iban):
+			add("iban", "has invalid check digits")
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// checkLength checks the presence and length of a field according to the rule.
+func checkLength(add func(field, format string, args ...interface{}), field, value string, rule lengthRule, country string) {
+	switch {
+	case value == "" && rule.presence == required:
+		add(field, "is required in %s", country)
+	case value == "":
+	case rule.presence == forbidden:
+		add(field, "is not supported in %s", country)
+	case rule.digits && !digitsPattern.MatchString(value):
+		add(field, "must be digits only")
+	case !rule.digits && !alphanumericPattern.MatchString(value):
+		add(field, "must be alphanumeric")
+	case rule.min == rule.max && len(value) != rule.min:
+		add(field, "must be %d characters", rule.min)
+	case len(value) < rule.min || len(value) > rule.max:
+		add(field, "must be between %d and %d characters", rule.min, rule.max)
+	}
+}
+
+// validIBANChecksum reports whether the check digits of the IBAN are valid, following ISO 13616: once the first
+// 4 characters are moved to the end and letters are replaced by numbers, the IBAN modulo 97 must be 1.
+func validIBANChecksum(iban string) bool {
+	var digits strings.Builder
+	for _, r := range iban[4:] + iban[:4] {
+		if r >= 'A' && r <= 'Z' {
+			digits.WriteString(fmt.Sprint(r - 'A' + 10))
+		} else {
+			digits.WriteRune(r)
+		}
+	}
+
+	number, ok := new(big.Int).SetString(digits.String(), 10)
+	return ok && new(big.Int).Mod(number, big.NewInt(97)).Int64() == 1
+}
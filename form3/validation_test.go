@@ -0,0 +1,170 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestCreateAccountAttributes_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes *CreateAccountAttributes
+		wantErrs   ValidationErrors
+	}{
+		{
+			name: "Test valid GB account",
+			attributes: &CreateAccountAttributes{
+				BankID:        "400300",
+				BankIDCode:    "GBDSC",
+				Bic:           "NWBKGB22",
+				Country:       "GB",
+				AccountNumber: ToPointer("41426819"),
+				Iban:          ToPointer("GB16NWBK40030041426819"),
+			},
+		},
+		{
+			name: "Test valid FR account",
+			attributes: &CreateAccountAttributes{
+				BankID:        "2004101005",
+				BankIDCode:    "FR",
+				Country:       "FR",
+				AccountNumber: ToPointer("0500013M02"),
+			},
+		},
+		{
+			name: "Test valid NL account without bank ID",
+			attributes: &CreateAccountAttributes{
+				Bic:     "ABNANL2A",
+				Country: "NL",
+				Iban:    ToPointer("NL91ABNA0417164300"),
+			},
+		},
+		{
+			name:       "Test unknown country only gets generic checks",
+			attributes: &CreateAccountAttributes{BankID: "anything", Country: "ZZ"},
+		},
+		{
+			name:       "Test missing attributes",
+			attributes: nil,
+			wantErrs:   ValidationErrors{{Field: "attributes", Message: "are required"}},
+		},
+		{
+			name:       "Test missing country",
+			attributes: &CreateAccountAttributes{Bic: "NWBKGB22"},
+			wantErrs:   ValidationErrors{{Field: "country", Message: "is required"}},
+		},
+		{
+			name: "Test invalid GB account",
+			attributes: &CreateAccountAttributes{
+				BankID:        "40030",
+				BankIDCode:    "GB",
+				Country:       "GB",
+				AccountNumber: ToPointer("4142681A"),
+			},
+			wantErrs: ValidationErrors{
+				{Field: "bank_id", Message: "must be 6 characters"},
+				{Field: "bank_id_code", Message: "must be GBDSC"},
+				{Field: "bic", Message: "is required in GB"},
+				{Field: "account_number", Message: "must be digits only"},
+			},
+		},
+		{
+			name: "Test invalid FR account",
+			attributes: &CreateAccountAttributes{
+				BankID:     "20041",
+				BankIDCode: "FR",
+				Bic:        "NWBKFR4",
+				Country:    "FR",
+			},
+			wantErrs: ValidationErrors{
+				{Field: "bic", Message: "must be 8 or 11 characters in the BIC format"},
+				{Field: "bank_id", Message: "must be 10 characters"},
+			},
+		},
+		{
+			name:       "Test DE bank ID code",
+			attributes: &CreateAccountAttributes{BankID: "37040044", BankIDCode: "DE", Country: "DE"},
+			wantErrs:   ValidationErrors{{Field: "bank_id_code", Message: "must be DEBLZ"}},
+		},
+		{
+			name:       "Test forbidden bank ID",
+			attributes: &CreateAccountAttributes{BankID: "123", BankIDCode: "NLBANK", Bic: "ABNANL2A", Country: "NL"},
+			wantErrs: ValidationErrors{
+				{Field: "bank_id", Message: "is not supported in NL"},
+				{Field: "bank_id_code", Message: "is not supported in NL"},
+			},
+		},
+		{
+			name:       "Test account number length range",
+			attributes: &CreateAccountAttributes{BankID: "011000015", BankIDCode: "USABA", Bic: "FRNYUS33", Country: "US", AccountNumber: ToPointer("12345")},
+			wantErrs:   ValidationErrors{{Field: "account_number", Message: "must be between 6 and 17 characters"}},
+		},
+		{
+			name:       "Test IBAN not supported",
+			attributes: &CreateAccountAttributes{BankID: "011000015", BankIDCode: "USABA", Bic: "FRNYUS33", Country: "US", Iban: ToPointer("US11")},
+			wantErrs:   ValidationErrors{{Field: "iban", Message: "is not supported in US"}},
+		},
+		{
+			name:       "Test IBAN of another country",
+			attributes: &CreateAccountAttributes{BankID: "400300", BankIDCode: "GBDSC", Bic: "NWBKGB22", Country: "GB", Iban: ToPointer("DE89370400440532013000")},
+			wantErrs:   ValidationErrors{{Field: "iban", Message: "must start with the country code GB"}},
+		},
+		{
+			name:       "Test IBAN length",
+			attributes: &CreateAccountAttributes{BankID: "400300", BankIDCode: "GBDSC", Bic: "NWBKGB22", Country: "GB", Iban: ToPointer("GB11NWBK4003004142681")},
+			wantErrs:   ValidationErrors{{Field: "iban", Message: "must be 22 characters in GB"}},
+		},
+		{
+			name:       "Test IBAN check digits",
+			attributes: &CreateAccountAttributes{BankID: "400300", BankIDCode: "GBDSC", Bic: "NWBKGB22", Country: "GB", Iban: ToPointer("GB11NWBK40030041426819")},
+			wantErrs:   ValidationErrors{{Field: "iban", Message: "has invalid check digits"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.attributes.Validate()
+			if tt.wantErrs == nil {
+				if err != nil {
+					t.Fatalf("CreateAccountAttributes.Validate() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var errs ValidationErrors
+			if !errors.As(err, &errs) || !errors.Is(err, ErrValidation) {
+				t.Fatalf("CreateAccountAttributes.Validate() error = %v, want ValidationErrors", err)
+			}
+			if !reflect.DeepEqual(errs, tt.wantErrs) {
+				t.Fatalf("CreateAccountAttributes.Validate() - errors - got = %v, want %v", errs, tt.wantErrs)
+			}
+		})
+	}
+}
+
+func TestAccountService_Create_Validation(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data": {"id": "10"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	_, res, err := client.Account.Create(context.Background(), "10", "", &CreateAccountAttributes{Country: "GB"})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("AccountService.Create() error = %v, want %v", err, ErrValidation)
+	}
+	if res != nil || requests != 0 {
+		t.Fatalf("AccountService.Create() - requests - got = %d, want none", requests)
+	}
+}
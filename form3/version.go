@@ -0,0 +1,144 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrMissingVersion is returned when a resource needed for an optimistic concurrency operation has no version.
+var ErrMissingVersion = errors.New("resource has no version")
+
+// ErrVersionConflict is matched by errors.Is on every VersionConflictError.
+var ErrVersionConflict = errors.New("resource version conflict")
+
+// VersionConflictError is returned when a resource is changed or deleted with a version which is not its current one,
+// usually because somebody else changed it in between. It also matches ErrConflict.
+type VersionConflictError struct {
+	// ID is the ID of the resource.
+	ID string
+
+	// Version is the version which was given.
+	Version int64
+
+	// Err is the error returned by the Form3 API.
+	Err error
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s: %s is not at version %d: %v", ErrVersionConflict, e.ID, e.Version, e.Err)
+}
+
+// Is reports whether the target is ErrVersionConflict.
+func (e *VersionConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
+
+func (e *VersionConflictError) Unwrap() error {
+	return e.Err
+}
+
+// versionConflict returns a *VersionConflictError if the error is a conflict, and the error itself otherwise.
+func versionConflict(ID string, version int64, err error) error {
+	if !errors.Is(err, ErrConflict) {
+		return err
+	}
+	return &VersionConflictError{ID: ID, Version: version, Err: err}
+}
+
+// Versioned is implemented by the resources carrying a version, which the Form3 API uses for optimistic concurrency:
+// updates and deletions must give the current version of the resource and fail with a 409 status code otherwise.
+// ApplyLatestVersion works with any of them.
+type Versioned interface {
+	GetVersion() *int64
+}
+
+// GetVersion returns the version of the account, or nil if unknown.
+func (a *Account) GetVersion() *int64 {
+	if a == nil {
+		return nil
+	}
+	return a.Version
+}
+
+// VersionRetryPolicy configures how operations needing the latest version of a resource, such as
+// AccountService.DeleteLatest, are retried when the resource changed between fetching it and using its version.
+type VersionRetryPolicy struct {
+	// MaxAttempts is the maximum number of times the resource is fetched, including the first one.
+	// Values lower than 2 disable retries.
+	MaxAttempts int
+}
+
+// maxAttempts returns the maximum number of attempts allowed by the policy, which can be nil.
+func (p *VersionRetryPolicy) maxAttempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// ApplyLatestVersion fetches the latest version of a resource and calls apply with it, for example to update or
+// delete the resource whatever its current version. If apply fails with a version conflict, the resource is fetched
+// again and apply is retried according to the policy, which can be nil to disable retries. The conflict is returned
+// as is when retries are disabled.
+func ApplyLatestVersion[T Versioned, R any](ctx context.Context, policy *VersionRetryPolicy, fetch func(ctx context.Context) (T, error), apply func(ctx context.Context, version int64) (R, error)) (R, error) {
+	var (
+		result R
+		err    error
+	)
+
+	attempts := policy.maxAttempts()
+	for attempt := 1; attempt <= attempts; attempt++ {
+		resource, fetchErr := fetch(ctx)
+		if fetchErr != nil {
+			return result, fetchErr
+		}
+
+		version := resource.GetVersion()
+		if version == nil {
+			return result, ErrMissingVersion
+		}
+
+		result, err = apply(ctx, *version)
+		if !errors.Is(err, ErrConflict) {
+			return result, err
+		}
+	}
+
+	if attempts == 1 {
+		return result, err
+	}
+	return result, fmt.Errorf("resource kept changing after %d attempts: %w", attempts, err)
+}
+
+// DeleteLatest deletes an account whatever its current version, which is fetched first, skipping the client cache.
+// If the account changes in between, the deletion is retried according to the client VersionRetry policy.
+func (as *AccountService) DeleteLatest(ctx context.Context, ID string) (*Response, error) {
+	deleteVersion := func(ctx context.Context, version int64) (*Response, error) {
+		return as.Delete(ctx, ID, version)
+	}
+
+	return ApplyLatestVersion(ctx, as.client.VersionRetry, as.latest(ID), deleteVersion)
+}
+
+// UpdateLatest updates an account whatever its current version, which is fetched first, skipping the client cache.
+// If the account changes in between, the update is retried according to the client VersionRetry policy.
+func (as *AccountService) UpdateLatest(ctx context.Context, ID string, attributes *UpdateAccountAttributes) (*Account, *Response, error) {
+	var res *Response
+	updateVersion := func(ctx context.Context, version int64) (*Account, error) {
+		account, updateRes, err := as.Update(ctx, ID, version, attributes)
+		res = updateRes
+		return account, err
+	}
+
+	account, err := ApplyLatestVersion(ctx, as.client.VersionRetry, as.latest(ID), updateVersion)
+	return account, res, err
+}
+
+// latest returns a function fetching the latest version of an account from the Form3 API.
+func (as *AccountService) latest(ID string) func(ctx context.Context) (*Account, error) {
+	return func(ctx context.Context) (*Account, error) {
+		account, _, err := as.fetchAccount(ctx, ID, true)
+		return account, err
+	}
+}
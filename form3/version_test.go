@@ -0,0 +1,161 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAccountService_DeleteLatest(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []Option
+		conflicts    int
+		noVersion    bool
+		prefetch     bool
+		wantErr      error
+		wantRetried  bool
+		wantRequests []string
+	}{
+		{
+			name:         "Test account is deleted with its current version",
+			wantRequests: []string{"GET", "DELETE version=1"},
+		},
+		{
+			name:         "Test conflict is retried with the new version",
+			opts:         []Option{WithVersionRetry(3)},
+			conflicts:    1,
+			wantRequests: []string{"GET", "DELETE version=1", "GET", "DELETE version=2"},
+		},
+		{
+			name:         "Test conflict is not retried by default",
+			conflicts:    1,
+			wantErr:      ErrConflict,
+			wantRequests: []string{"GET", "DELETE version=1"},
+		},
+		{
+			name:         "Test retries are bounded",
+			opts:         []Option{WithVersionRetry(2)},
+			conflicts:    5,
+			wantErr:      ErrConflict,
+			wantRetried:  true,
+			wantRequests: []string{"GET", "DELETE version=1", "GET", "DELETE version=2"},
+		},
+		{
+			name:         "Test cached account is fetched again",
+			opts:         []Option{WithCache(nil, time.Minute)},
+			prefetch:     true,
+			wantRequests: []string{"GET", "GET", "DELETE version=2"},
+		},
+		{
+			name:         "Test account without version",
+			noVersion:    true,
+			wantErr:      ErrMissingVersion,
+			wantRequests: []string{"GET"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			version, conflicts := 0, tt.conflicts
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					requests = append(requests, r.Method)
+					// Every fetch sees a new version, as if somebody else kept updating the account.
+					version++
+					if tt.noVersion {
+						w.Write([]byte(`{"data": {"id": "10"}}`))
+						return
+					}
+					w.Write([]byte(fmt.Sprintf(`{"data": {"id": "10", "version": %d}}`, version)))
+					return
+				}
+
+				requests = append(requests, r.Method+" "+r.URL.RawQuery)
+				if conflicts > 0 {
+					conflicts--
+					w.WriteHeader(http.StatusConflict)
+					w.Write([]byte(`{"error_message": "invalid version"}`))
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			client, err := NewClient(append([]Option{WithBaseURL(server.URL), WithHTTPClient(server.Client())}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			if tt.prefetch {
+				if _, _, err := client.Account.Fetch(context.Background(), "10"); err != nil {
+					t.Fatalf("AccountService.Fetch() error = %v", err)
+				}
+			}
+
+			_, err = client.Account.DeleteLatest(context.Background(), "10")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AccountService.DeleteLatest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if retried := err != nil && strings.Contains(err.Error(), "kept changing"); retried != tt.wantRetried {
+				t.Fatalf("AccountService.DeleteLatest() error = %v, want retried %v", err, tt.wantRetried)
+			}
+			if !reflect.DeepEqual(requests, tt.wantRequests) {
+				t.Fatalf("AccountService.DeleteLatest() - requests - got = %v, want %v", requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestAccountService_UpdateLatest(t *testing.T) {
+	var requests []string
+	version, conflicts := 0, 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			requests = append(requests, r.Method)
+			version++
+			w.Write([]byte(fmt.Sprintf(`{"data": {"id": "10", "version": %d}}`, version)))
+			return
+		}
+
+		var body UpdateAccountRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("json.Decode() error = %v", err)
+		}
+		requests = append(requests, fmt.Sprintf("%s version=%d", r.Method, body.Data.Version))
+		if conflicts > 0 {
+			conflicts--
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error_message": "invalid version"}`))
+			return
+		}
+		w.Write([]byte(fmt.Sprintf(`{"data": {"id": "10", "version": %d}}`, body.Data.Version+1)))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithVersionRetry(2))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	account, res, err := client.Account.UpdateLatest(context.Background(), "10", &UpdateAccountAttributes{Name: &[]string{"Jane Doe"}})
+	if err != nil {
+		t.Fatalf("AccountService.UpdateLatest() error = %v", err)
+	}
+	if account.Version == nil || *account.Version != 3 || res.StatusCode != http.StatusOK {
+		t.Fatalf("AccountService.UpdateLatest() - got = %v, %v, want version 3", account.Version, res.StatusCode)
+	}
+
+	wantRequests := []string{"GET", "PATCH version=1", "GET", "PATCH version=2"}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Fatalf("AccountService.UpdateLatest() - requests - got = %v, want %v", requests, wantRequests)
+	}
+}
@@ -64,7 +64,7 @@ func Test_DeleteAccount(t *testing.T) {
 			}
 
 			// delete test case account
-			err := client.Account.Delete(context.Background(), tc.accountID, tc.version)
+			_, err := client.Account.Delete(context.Background(), tc.accountID, tc.version)
 
 			// check errors
 			if tc.expectAPIError {
@@ -98,14 +98,8 @@ func deleteAccountExistsChecker(t *testing.T, accountID string) error {
 		return fmt.Errorf("account should not exists")
 	}
 
-	unwrappedErr := errors.Unwrap(err)
-	form3Err, ok := unwrappedErr.(*form3.Form3APIError)
-	if !ok {
-		return fmt.Errorf("expected Form3APIError but got %T", err)
-	}
-
-	if form3Err.StatusCode != 404 {
-		return fmt.Errorf("expected status code 404 but got %d", form3Err.StatusCode)
+	if !errors.Is(err, form3.ErrNotFound) {
+		return fmt.Errorf("expected not found error but got %v", err)
 	}
 
 	return nil
@@ -0,0 +1,76 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agatticelli/form3-client-go/form3"
+)
+
+func Test_ListAccounts(t *testing.T) {
+	cases := []struct {
+		name          string
+		opts          *form3.ListAccountsOptions
+		expectedCount int
+	}{
+		{
+			name:          "List accounts without options",
+			expectedCount: 1,
+		},
+		{
+			name:          "List accounts filtered by country",
+			opts:          &form3.ListAccountsOptions{Country: "FR"},
+			expectedCount: 1,
+		},
+		{
+			name:          "List accounts of an empty page",
+			opts:          &form3.ListAccountsOptions{PageNumber: 1, PageSize: 1},
+			expectedCount: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Helper()
+
+			client := initClient(t)
+
+			// seed database with a single account
+			accountsTableSeeder(t, client)
+
+			accounts, _, err := client.Account.List(context.Background(), tc.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(accounts) != tc.expectedCount {
+				t.Fatalf("expected %d accounts but got %d", tc.expectedCount, len(accounts))
+			}
+		})
+	}
+}
+
+func Test_ListAllAccounts(t *testing.T) {
+	client := initClient(t)
+
+	// seed database with a single account and two more, listed a page of one account at a time
+	accountsTableSeeder(t, client)
+	for _, id := range []string{"a4b93ff1-1d3c-4c2e-9d6d-5b7a3cbf1a01", "a4b93ff1-1d3c-4c2e-9d6d-5b7a3cbf1a02"} {
+		createAccountData := accountFactory(id, "FR")
+		if _, _, err := client.Account.Create(context.Background(), createAccountData.ID, createAccountData.OrganisationID, createAccountData.Attributes); err != nil {
+			t.Fatalf("error seeding account: %v", err)
+		}
+	}
+
+	count := 0
+	for _, err := range client.Account.All(context.Background(), &form3.ListAccountsOptions{PageSize: 1}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		count++
+	}
+
+	if count != 3 {
+		t.Fatalf("expected 3 accounts but got %d", count)
+	}
+}
@@ -2,7 +2,6 @@ package integration
 
 import (
 	"context"
-	"net/http"
 	"testing"
 
 	"github.com/agatticelli/form3-client-go/form3"
@@ -17,12 +16,12 @@ func accountFactory(ID, country string) *form3.CreateAccountData {
 		ID:             ID,
 		OrganisationID: uuid.New().String(),
 		Attributes: &form3.CreateAccountAttributes{
-			BankID:        "20041",
+			BankID:        "2004101005",
 			BankIDCode:    "FR",
 			Bic:           "NWBKFR42",
 			Name:          []string{"Alan Gatticelli"},
 			Country:       country,
-			AccountNumber: form3.ToPointer("31926819"),
+			AccountNumber: form3.ToPointer("0500013M02"),
 		},
 	}
 }
@@ -43,14 +42,20 @@ func accountsTableSeeder(t *testing.T, client *form3.Client) {
 func truncateAccountsTable(t *testing.T, client *form3.Client) {
 	t.Helper()
 
-	var accountList form3.Form3BodyResponse[[]form3.Account]
-	// We need to fetch all accounts to delete them.
-	err := client.Do(context.Background(), http.MethodGet, "organisation/accounts", nil, &accountList)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	for _, account := range accountList.Data {
-		client.Account.Delete(context.Background(), account.ID, *account.Version)
+	// We need to fetch all accounts to delete them. Deleted accounts leave the list, so we always read the first page.
+	for {
+		accounts, _, err := client.Account.List(context.Background(), &form3.ListAccountsOptions{PageSize: 100})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(accounts) == 0 {
+			return
+		}
+
+		for _, account := range accounts {
+			if _, err := client.Account.Delete(context.Background(), account.ID, *account.Version); err != nil {
+				t.Fatal(err)
+			}
+		}
 	}
 }
@@ -1,7 +1,6 @@
 package integration
 
 import (
-	"net/url"
 	"os"
 	"testing"
 
@@ -15,14 +14,12 @@ var client *form3.Client
 func initClient(t *testing.T) *form3.Client {
 	var err error
 
-	testingBaseURL := os.Getenv("FORM3_API_BASE_URL")
+	testingBaseURL := os.Getenv(form3.EnvBaseURL)
 	if testingBaseURL == "" {
 		testingBaseURL = defaultTestingBaseURL
 	}
 
-	client = form3.NewClient(nil)
-	client.BaseURL, err = url.Parse(testingBaseURL)
-
+	client, err = form3.NewClient(form3.WithBaseURL(testingBaseURL))
 	if err != nil {
 		t.Fatalf("error setting up client: %v", err)
 	}